@@ -0,0 +1,129 @@
+/*
+Package api provides a stable interface for embedding Vale in other Go
+programs.
+
+Unlike the command-line tool, a Linter never prints anything or reads the
+working directory for its configuration: it's built from a core.Config (or
+the contents of a .vale.ini file) and returns its results.
+
+	linter, err := api.NewFromINI(ini, "/path/to/project")
+	if err != nil {
+		return err
+	}
+	result, err := linter.LintBytes(ctx, "README.md", content)
+*/
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+)
+
+// A Linter lints content according to a single config.
+//
+// It's safe for concurrent use.
+type Linter struct {
+	linter lint.Linter
+}
+
+// A Result holds the alerts for a single file.
+type Result struct {
+	Path   string       // the path given for (or found for) the content
+	Alerts []core.Alert // sorted by line and column
+	Errors []core.Error // problems that kept us from fully linting the file (e.g., a timeout)
+}
+
+// RuleErrors are the problems with a config's rules (which are only
+// recorded if its Strict option is set).
+type RuleErrors []check.RuleError
+
+func (e RuleErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	noun := "problems"
+	if len(e) == 1 {
+		noun = "problem"
+	}
+	return fmt.Sprintf("found %d %s in our rules:\n%s", len(e), noun, strings.Join(msgs, "\n"))
+}
+
+// New creates a Linter for `config`, loading the styles it references.
+//
+// If `config.Strict` is set, any problem with the rules is returned as
+// RuleErrors.
+func New(config *core.Config) (*Linter, error) {
+	mgr := check.NewManager(config)
+	if len(mgr.RuleErrors) > 0 {
+		return nil, RuleErrors(mgr.RuleErrors)
+	}
+	return &Linter{linter: lint.Linter{Config: config, CheckManager: mgr}}, nil
+}
+
+// NewFromINI creates a Linter from the contents of a .vale.ini file, `src`,
+// resolving any relative paths in it (e.g., StylesPath) against `dir`.
+func NewFromINI(src []byte, dir string) (*Linter, error) {
+	config, err := core.ParseConfig(src, dir)
+	if err != nil {
+		return nil, err
+	}
+	return New(config)
+}
+
+// Errors returns the problems we ran into while loading our rules (e.g., a
+// rule with invalid YAML), which are skipped.
+func (l *Linter) Errors() []core.Error {
+	return l.linter.CheckManager.Errors
+}
+
+// LintBytes lints `data` according to the format of `name` (e.g.,
+// "README.md"), which also determines the config sections that apply to it.
+// Nothing is read from disk.
+func (l *Linter) LintBytes(ctx context.Context, name string, data []byte) (Result, error) {
+	files, err := l.linter.LintStringAsContext(ctx, string(data), name)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(files[0]), nil
+}
+
+// LintPaths lints every supported file in `paths`, which may be files or
+// directories, returning a Result for each one (sorted by path).
+//
+// It's an error for any path not to exist. If `ctx` is canceled, we stop
+// linting and return its error; a file that exceeds the config's Timeout is
+// reported in its Result's Errors.
+func (l *Linter) LintPaths(ctx context.Context, paths []string) ([]Result, error) {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+
+	results := []Result{}
+	err := l.linter.LintEachContext(ctx, paths, "*", func(f *core.File) error {
+		results = append(results, newResult(f))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, nil
+}
+
+func newResult(f *core.File) Result {
+	return Result{Path: f.Path, Alerts: f.SortedAlerts(), Errors: f.Errors}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintBytes(t *testing.T) {
+	linter, err := New(core.NewConfig())
+	assert.Nil(t, err)
+
+	result, err := linter.LintBytes(context.Background(), "docs/intro.md", []byte("This is *very* good.\n\n    very\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "docs/intro.md", result.Path)
+	assert.Len(t, result.Alerts, 1)
+	assert.Equal(t, "vale.Editorializing", result.Alerts[0].Check)
+	assert.Equal(t, []int{10, 13}, result.Alerts[0].Span)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = linter.LintBytes(ctx, "intro.md", []byte("It is very good."))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestNewFromINI(t *testing.T) {
+	src := []byte("MinAlertLevel = error\n\n[*.md]\nvale.Editorializing = error\n")
+	linter, err := NewFromINI(src, "")
+	assert.Nil(t, err)
+
+	result, err := linter.LintBytes(context.Background(), "a.md", []byte("It is very good.\n"))
+	assert.Nil(t, err)
+	assert.Len(t, result.Alerts, 1)
+	assert.Equal(t, "error", result.Alerts[0].Severity)
+
+	result, err = linter.LintBytes(context.Background(), "a.txt", []byte("It is very good.\n"))
+	assert.Nil(t, err)
+	assert.Len(t, result.Alerts, 0)
+
+	_, err = NewFromINI([]byte("[*.md\n"), "")
+	assert.NotNil(t, err)
+}
+
+func TestLintPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"b.md", "a.txt"} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte("It is very good.\n"), 0644)
+		assert.Nil(t, err)
+	}
+
+	linter, err := New(core.NewConfig())
+	assert.Nil(t, err)
+
+	results, err := linter.LintPaths(context.Background(), []string{dir})
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, filepath.Join(dir, "a.txt"), results[0].Path)
+	assert.Equal(t, []int{7, 10}, results[1].Alerts[0].Span)
+
+	_, err = linter.LintPaths(context.Background(), []string{filepath.Join(dir, "missing.md")})
+	assert.True(t, os.IsNotExist(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = linter.LintPaths(ctx, []string{dir})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestLintPathsIgnoresWorkingDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "docs"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".valeignore"), []byte("*.md\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "docs", "a.md"), []byte("It is very good.\n"), 0644))
+
+	// The embedding program's working directory shouldn't affect our results.
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	defer os.Chdir(cwd)
+	assert.Nil(t, os.Chdir(dir))
+
+	linter, err := New(core.NewConfig())
+	assert.Nil(t, err)
+
+	results, err := linter.LintPaths(context.Background(), []string{"docs"})
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Len(t, results[0].Alerts, 1)
+}
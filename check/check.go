@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ValeLint/gospell"
 	"github.com/ValeLint/vale/core"
@@ -40,6 +42,15 @@ type ruleFn func(string, *core.File) []core.Alert
 type Manager struct {
 	AllChecks map[string]Check
 	Config    *core.Config
+	UsesPOS   bool // whether any check needs our part-of-speech tagger
+
+	// RuleErrors holds the problems with the rules we've loaded, which are
+	// only recorded if `Config.Strict` is set.
+	RuleErrors []RuleError
+
+	// Errors holds the problems that kept us from loading a rule (e.g., an
+	// invalid regex), which are always recorded.
+	Errors []core.Error
 }
 
 // NewManager creates a new Manager and loads the rule definitions (that is,
@@ -95,13 +106,49 @@ func NewManager(config *core.Config) *Manager {
 			// individually.
 			fName := parts[1] + ".yml"
 			path = filepath.Join(baseDir, parts[0], fName)
-			core.CheckError(mgr.loadCheck(fName, path))
+			mgr.addError(chk, path, mgr.loadCheck(fName, path))
 		}
 	}
 
 	return &mgr
 }
 
+// A RuleInfo describes a loaded rule, where it came from, and where it's
+// enabled.
+type RuleInfo struct {
+	Name     string   // e.g., "18F.Clarity"
+	Style    string   // e.g., "18F"
+	Extends  string   // the extension point
+	Level    string   // the level after any config overrides
+	Scope    string   // the scope the rule applies to
+	Path     string   // the YAML file that defined the rule, if any
+	Sections []string // the config sections that enable the rule
+}
+
+// Rules describes every rule loaded by mgr, sorted by name.
+func (mgr *Manager) Rules() []RuleInfo {
+	seen := map[string]bool{}
+	rules := []RuleInfo{}
+	for name, chk := range mgr.AllChecks {
+		key, extends := name, chk.Extends
+		if strings.Contains(extends, ".") {
+			// Consistency checks are loaded once per pair of options (e.g.,
+			// "Style.Rule.v1"), each extending their rule's name.
+			name, extends = extends, "consistency"
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+		}
+		rules = append(rules, RuleInfo{
+			Name: name, Style: strings.Split(name, ".")[0], Extends: extends,
+			Level: core.AlertLevels[chk.Level], Scope: chk.Scope.Value,
+			Path: chk.Path, Sections: mgr.Config.Sections(key)})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules
+}
+
 func makeRegexp(template string, noCase bool, word func() bool, callback func() string) string {
 	regex := ""
 	if noCase {
@@ -252,13 +299,21 @@ func checkSubstitution(txt string, chk Substitution, f *core.File, r *regexp.Reg
 						//
 						// If it doesn't match, the alert doesn't get added to
 						// a File (i.e., `hide` == true).
+						start := time.Now()
 						pos = core.CheckPOS(loc, chk.POS, txt)
+						f.Profile.AddPhase(f.Path, core.PhaseTagging, start)
 					}
 					a := core.Alert{
 						Check: chk.Name, Severity: chk.Level, Span: loc,
 						Link: chk.Link, Hide: pos, Match: observed}
 					a.Message, a.Description = formatMessages(chk.Message,
 						chk.Description, expected, observed)
+					if strings.Count(chk.Message, "%s") > 1 {
+						// Some styles (e.g., 18F.Clarity) use the swap value
+						// as advice rather than as a replacement -- we only
+						// treat it as one if the message mentions both.
+						a.Suggestions = suggestionsFrom(expected)
+					}
 					alerts = append(alerts, a)
 				}
 			}
@@ -268,6 +323,38 @@ func checkSubstitution(txt string, chk Substitution, f *core.File, r *regexp.Reg
 	return alerts
 }
 
+var reQuoted = regexp.MustCompile(`'([^']+)'`)
+
+// reSentence matches a swap value that reads as a sentence: several words
+// ending with a period, question mark or exclamation point.
+var reSentence = regexp.MustCompile(`\s.*[.!?]['"’”)]?\s*$`)
+
+// suggestionsFrom extracts the replacements offered by a swap value -- e.g.,
+// "'later' or 'next'" => []string{"later", "next"}.
+func suggestionsFrom(expected string) []string {
+	options := []string{}
+	if reSentence.MatchString(expected) {
+		// Advice (e.g., "Avoid using 'agenda'.") isn't a replacement.
+		return options
+	}
+	if matches := reQuoted.FindAllStringSubmatch(expected, -1); len(matches) > 0 {
+		for _, m := range matches {
+			options = append(options, m[1])
+		}
+	} else {
+		options = append(options, strings.TrimSpace(expected))
+	}
+
+	suggestions := []string{}
+	for _, opt := range options {
+		// Notation such as "bit-field(s)" isn't meant to be taken literally.
+		if opt != "" && !strings.ContainsAny(opt, "()") {
+			suggestions = append(suggestions, opt)
+		}
+	}
+	return suggestions
+}
+
 func checkConsistency(txt string, chk Consistency, f *core.File, r *regexp.Regexp, opts []string) []core.Alert {
 	alerts := []core.Alert{}
 	loc := []int{}
@@ -388,7 +475,7 @@ func (mgr *Manager) addCapitalizationCheck(chkName string, chkDef Capitalization
 		chkDef.Check = f
 	} else {
 		re, err := regexp.Compile(chkDef.Match)
-		if !core.CheckError(err) {
+		if !mgr.addError(chkName, chkDef.Path, err) {
 			return
 		}
 		chkDef.Check = re.MatchString
@@ -418,7 +505,7 @@ func (mgr *Manager) addConsistencyCheck(chkName string, chkDef Consistency) {
 		chkRE = fmt.Sprintf("(?P<%s>%s)|(?P<%s>%s)", subs[0], v1, subs[1], v2)
 		chkRE = fmt.Sprintf(regex, chkRE)
 		re, err := regexp.Compile(chkRE)
-		if core.CheckError(err) {
+		if mgr.addError(chkName, chkDef.Path, err) {
 			chkDef.Extends = chkName
 			chkDef.Name = fmt.Sprintf("%s.%s", chkName, v1)
 			fn := func(text string, file *core.File) []core.Alert {
@@ -439,7 +526,7 @@ func (mgr *Manager) addExistenceCheck(chkName string, chkDef Existence) {
 
 	regex = fmt.Sprintf(regex, strings.Join(chkDef.Tokens, "|"))
 	re, err := regexp.Compile(regex)
-	if core.CheckError(err) {
+	if mgr.addError(chkName, chkDef.Path, err) {
 		fn := func(text string, file *core.File) []core.Alert {
 			return checkExistence(text, chkDef, file, re)
 		}
@@ -454,7 +541,7 @@ func (mgr *Manager) addRepetitionCheck(chkName string, chkDef Repetition) {
 	}
 	regex += `(` + strings.Join(chkDef.Tokens, "|") + `)`
 	re, err := regexp.Compile(regex)
-	if core.CheckError(err) {
+	if mgr.addError(chkName, chkDef.Path, err) {
 		fn := func(text string, file *core.File) []core.Alert {
 			return checkRepetition(text, chkDef, file, re)
 		}
@@ -464,7 +551,7 @@ func (mgr *Manager) addRepetitionCheck(chkName string, chkDef Repetition) {
 
 func (mgr *Manager) addOccurrenceCheck(chkName string, chkDef Occurrence) {
 	re, err := regexp.Compile(chkDef.Token)
-	if core.CheckError(err) && chkDef.Max >= 1 {
+	if mgr.addError(chkName, chkDef.Path, err) && chkDef.Max >= 1 {
 		fn := func(text string, file *core.File) []core.Alert {
 			return checkOccurrence(text, chkDef, file, re, chkDef.Max)
 		}
@@ -478,13 +565,13 @@ func (mgr *Manager) addConditionalCheck(chkName string, chkDef Conditional) {
 	var err error
 
 	re, err = regexp.Compile(chkDef.Second)
-	if !core.CheckError(err) {
+	if !mgr.addError(chkName, chkDef.Path, err) {
 		return
 	}
 	expression = append(expression, re)
 
 	re, err = regexp.Compile(chkDef.First)
-	if !core.CheckError(err) {
+	if !mgr.addError(chkName, chkDef.Path, err) {
 		return
 	}
 	expression = append(expression, re)
@@ -506,9 +593,7 @@ func (mgr *Manager) addSubstitutionCheck(chkName string, chkDef Substitution) {
 
 	replacements := []string{}
 	for regexstr, replacement := range chkDef.Swap {
-		opens := strings.Count(regexstr, "(")
-		if opens != strings.Count(regexstr, "?:") &&
-			opens != strings.Count(regexstr, `\(`) {
+		if hasCaptureGroup(regexstr) {
 			// We rely on manually-added capture groups to associate a match
 			// with its replacement -- e.g.,
 			//
@@ -529,10 +614,11 @@ func (mgr *Manager) addSubstitutionCheck(chkName string, chkDef Substitution) {
 
 	regex = fmt.Sprintf(regex, strings.TrimRight(tokens, "|"))
 	re, err := regexp.Compile(regex)
-	if core.CheckError(err) {
+	if mgr.addError(chkName, chkDef.Path, err) {
 		fn := func(text string, file *core.File) []core.Alert {
 			return checkSubstitution(text, chkDef, file, re, replacements)
 		}
+		mgr.UsesPOS = mgr.UsesPOS || chkDef.POS != ""
 		mgr.updateAllChecks(chkDef.Definition, fn)
 	}
 }
@@ -554,20 +640,32 @@ func (mgr *Manager) addSpellingCheck(chkName string, chkDef Spelling) {
 	if chkDef.Ignore != "" {
 		vocab, _ := filepath.Abs(chkDef.Ignore)
 		_, exists := model.AddWordListFile(vocab)
-		core.CheckError(exists)
+		mgr.addError(chkName, chkDef.Path, exists)
 	}
 
 	fn := func(text string, file *core.File) []core.Alert {
 		return checkSpelling(text, chkDef, model, file)
 	}
 
-	if core.CheckError(err) {
+	if mgr.addError(chkName, chkDef.Path, err) {
 		mgr.updateAllChecks(chkDef.Definition, fn)
 	}
 }
 
+// addError records a problem with loading the rule `name`, defined in the
+// file at `path` (which is empty for built-in rules), returning whether or
+// not `err` is nil.
+func (mgr *Manager) addError(name, path string, err error) bool {
+	if err != nil {
+		mgr.Errors = append(mgr.Errors, core.Error{
+			Kind: core.ErrorRule, Path: path, Rule: name, Message: err.Error()})
+	}
+	return err == nil
+}
+
 func (mgr *Manager) updateAllChecks(chkDef Definition, fn ruleFn) {
-	chk := Check{Rule: fn, Extends: chkDef.Extends, Code: chkDef.Code}
+	chk := Check{Rule: fn, Extends: chkDef.Extends, Code: chkDef.Code,
+		Description: chkDef.Description, Link: chkDef.Link, Path: chkDef.Path}
 	chk.Level = core.LevelToInt[chkDef.Level]
 	chk.Scope = core.Selector{Value: chkDef.Scope}
 	mgr.AllChecks[chkDef.Name] = chk
@@ -635,7 +733,7 @@ func validateDefinition(generic map[string]interface{}, name string) error {
 	return nil
 }
 
-func (mgr *Manager) addCheck(file []byte, chkName string) error {
+func (mgr *Manager) addCheck(file []byte, chkName, path string) error {
 	// Load the rule definition.
 	generic := map[string]interface{}{}
 	err := yaml.Unmarshal(file, &generic)
@@ -647,6 +745,7 @@ func (mgr *Manager) addCheck(file []byte, chkName string) error {
 
 	// Set default values, if necessary.
 	generic["name"] = chkName
+	generic["path"] = path
 	if level, ok := mgr.Config.RuleToLevel[chkName]; ok {
 		generic["level"] = level
 	} else if _, ok := generic["level"]; !ok {
@@ -666,25 +765,28 @@ func (mgr *Manager) loadExternalStyle(path string) {
 			if err != nil || fi.IsDir() {
 				return nil
 			}
-			core.CheckError(mgr.loadCheck(fi.Name(), fp))
+			mgr.addError(ruleName(fp), fp, mgr.loadCheck(fi.Name(), fp))
 			return nil
 		})
-	core.CheckError(err)
+	mgr.addError("", path, err)
 }
 
 func (mgr *Manager) loadCheck(fName string, fp string) error {
 	if strings.HasSuffix(fName, ".yml") {
 		f, err := ioutil.ReadFile(fp)
-		if !core.CheckError(err) {
+		if err != nil {
 			return err
 		}
 
-		style := filepath.Base(filepath.Dir(fp))
-		chkName := style + "." + strings.Split(fName, ".")[0]
+		if mgr.Config != nil && mgr.Config.Strict {
+			mgr.RuleErrors = append(mgr.RuleErrors, ValidateRule(fp, f)...)
+		}
+
+		chkName := ruleName(fp)
 		if _, ok := mgr.AllChecks[chkName]; ok {
 			return fmt.Errorf("(%s): duplicate check", chkName)
 		}
-		return mgr.addCheck(f, chkName)
+		return mgr.addCheck(f, chkName, fp)
 	}
 	return nil
 }
@@ -695,6 +797,13 @@ func (mgr *Manager) loadDefaultRules() {
 		if err != nil {
 			continue
 		}
-		core.CheckError(mgr.addCheck(b, "vale."+chk))
+		mgr.addError("vale."+chk, "", mgr.addCheck(b, "vale."+chk, ""))
 	}
 }
+
+// ruleName returns the name (e.g., "18F.Clarity") of the rule defined by the
+// YAML file `fp`.
+func ruleName(fp string) string {
+	style := filepath.Base(filepath.Dir(fp))
+	return style + "." + strings.Split(filepath.Base(fp), ".")[0]
+}
@@ -1,8 +1,14 @@
 package check
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/jdkato/regexp"
 )
 
 var checktests = []struct {
@@ -45,3 +51,130 @@ func TestFormatMessage(t *testing.T) {
 		}
 	}
 }
+
+var suggestiontests = []struct {
+	in  string
+	out []string
+}{
+	{"use", []string{"use"}},
+	{"'use'", []string{"use"}},
+	{"'later' or 'next'", []string{"later", "next"}},
+	{"it's", []string{"it's"}},
+	{"bit-field(s)", []string{}},
+	{"e.g.", []string{"e.g."}},
+	{"Avoid using 'advancing.'", []string{}},
+	{"Be more specific — we’re either doing something or we’re not.", []string{}},
+}
+
+func TestSuggestionsFrom(t *testing.T) {
+	for _, tt := range suggestiontests {
+		s := suggestionsFrom(tt.in)
+		if strings.Join(s, "|") != strings.Join(tt.out, "|") {
+			t.Errorf("%q => %v != %v", tt.in, s, tt.out)
+		}
+	}
+}
+
+func TestSubstitutionSuggestions(t *testing.T) {
+	f := core.NewFileFromString("", "test.md", core.NewConfig())
+	re := regexp.MustCompile(`(?i)\b(?:(utilize)|(leverage)|(agenda))\b`)
+	repl := []string{"use", "'use' or 'apply'", "Avoid using 'agenda'."}
+	for msg, expected := range map[string][]string{
+		"Use '%s' instead of '%s'.": {"use", "use|apply", ""},
+		"'%s'":                      {"", "", ""},
+	} {
+		chk := Substitution{Definition: Definition{Name: "Test.Simple", Message: msg}}
+		alerts := checkSubstitution("We utilize and leverage the agenda.", chk, f, re, repl)
+		if len(alerts) != 3 {
+			t.Fatalf("%q: expected 3 alerts, got %d", msg, len(alerts))
+		}
+		for i, a := range alerts {
+			if s := strings.Join(a.Suggestions, "|"); s != expected[i] {
+				t.Errorf("%q (%s): %q != %q", msg, a.Match, s, expected[i])
+			}
+		}
+	}
+}
+
+var invalidRule = `extends: substitution
+message: "Use '%s' instead of '%s'"
+level: warn
+ignorcase: true
+nonword: yes please
+swap:
+  utilize: use
+  '(foo)': bar
+  'ba[r': baz
+`
+
+func TestValidateRule(t *testing.T) {
+	expected := []string{
+		"Rule.yml:3: level: unknown level 'warn' (expected one of [suggestion warning error])",
+		"Rule.yml:4: ignorcase: unknown key for 'substitution'",
+		"Rule.yml:5: nonword: expected type 'bool', got unconvertible type 'string'",
+	}
+	got := []string{}
+	for _, e := range ValidateRule("Rule.yml", []byte(invalidRule)) {
+		got = append(got, e.Error())
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("%q != %q", got, expected)
+	}
+
+	fixed := strings.Replace(invalidRule, "nonword: yes please", "nonword: true", 1)
+	expected = append(expected[:2],
+		"Rule.yml:8: swap: '(foo)' has a group other than '(?:...)', so it's ignored",
+		"Rule.yml:9: swap: invalid regex 'ba[r': error parsing regexp: missing closing ]: `[r`")
+	got = []string{}
+	for _, e := range ValidateRule("Rule.yml", []byte(fixed)) {
+		got = append(got, e.Error())
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("%q != %q", got, expected)
+	}
+}
+
+func TestValidateMalformedRule(t *testing.T) {
+	for src, expected := range map[string]string{
+		"extends: existence\nmessage: \"Found '%s'\"\ntokens: foo: bar\n": "Rule.yml:3: -: mapping values are not allowed in this context",
+		"extends: existence\ntokens:\n  - foo\n bar: baz\n":               "Rule.yml:4: -: did not find expected key",
+		"- extends: existence\n":                                          "Rule.yml:1: -: cannot unmarshal !!seq into map[string]interface {}",
+	} {
+		errs := ValidateRule("Rule.yml", []byte(src))
+		if len(errs) != 1 || errs[0].Error() != expected {
+			t.Errorf("%q => %v != %q", src, errs, expected)
+		}
+	}
+}
+
+func TestManagerErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "styles")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rule := "extends: existence\nmessage: \"Found '%s'\"\ntokens:\n  - '(unclosed'\n"
+	if err = os.MkdirAll(filepath.Join(dir, "Bad"), 0755); err != nil {
+		panic(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "Bad", "Regex.yml"), []byte(rule), 0644); err != nil {
+		panic(err)
+	}
+
+	config := core.NewConfig()
+	config.StylesPath = dir
+	config.GBaseStyles = []string{"Bad"}
+
+	mgr := NewManager(config)
+	if len(mgr.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", mgr.Errors)
+	}
+	e := mgr.Errors[0]
+	if e.Kind != core.ErrorRule || e.Rule != "Bad.Regex" || e.Path != filepath.Join(dir, "Bad", "Regex.yml") {
+		t.Errorf("unexpected error: %#v", e)
+	}
+	if _, ok := mgr.AllChecks["Bad.Regex"]; ok {
+		t.Error("loaded a rule with an invalid regex")
+	}
+}
@@ -4,11 +4,14 @@ import "github.com/ValeLint/vale/core"
 
 // A Check implements a single rule.
 type Check struct {
-	Extends string
-	Code    bool
-	Level   int
-	Rule    ruleFn
-	Scope   core.Selector
+	Description string
+	Extends     string
+	Code        bool
+	Level       int
+	Link        string
+	Path        string // the YAML file that defined this check, if any
+	Rule        ruleFn
+	Scope       core.Selector
 }
 
 // Definition holds the common attributes of rule definitions.
@@ -20,6 +23,7 @@ type Definition struct {
 	Link        string
 	Message     string
 	Name        string
+	Path        string
 	Scope       string
 }
 
@@ -10,6 +10,13 @@ level: warning
 description: '...'
 # A link the source or reference.
 link: '...'
+# (optional) Text that this rule should and shouldn't flag, as checked by
+# 'vale test'.
+# examples:
+#   valid:
+#     - '...'
+#   invalid:
+#     - '...'
 %s`
 
 var existenceTemplate = `extends: existence
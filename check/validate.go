@@ -0,0 +1,263 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/jdkato/regexp"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+)
+
+// A RuleError is a problem with a rule's definition.
+type RuleError struct {
+	Path    string // the rule's YAML file
+	Line    int    // the (1-based) line of `Key`, if known
+	Key     string // the offending key
+	Message string
+}
+
+func (e RuleError) Error() string {
+	return fmt.Sprintf("%s:%d: %s: %s", e.Path, e.Line, e.Key, e.Message)
+}
+
+// definitionTypes maps each extension point to the type of its definition.
+var definitionTypes = map[string]reflect.Type{
+	"capitalization": reflect.TypeOf(Capitalization{}),
+	"conditional":    reflect.TypeOf(Conditional{}),
+	"consistency":    reflect.TypeOf(Consistency{}),
+	"existence":      reflect.TypeOf(Existence{}),
+	"occurrence":     reflect.TypeOf(Occurrence{}),
+	"readability":    reflect.TypeOf(Readability{}),
+	"repetition":     reflect.TypeOf(Repetition{}),
+	"spelling":       reflect.TypeOf(Spelling{}),
+	"substitution":   reflect.TypeOf(Substitution{}),
+}
+
+// reErrorKey finds the key named in a `mapstructure` error.
+var reErrorKey = regexp.MustCompile(`^'([^'\[.]*)[^']*' `)
+
+// reYAMLLine finds the line named in a `yaml` error (e.g., "yaml: line 3:
+// mapping values are not allowed in this context").
+var reYAMLLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.+)`)
+
+// FindRules maps the name of each rule on `stylesPath` (e.g.,
+// "18F.Clarity") to its YAML file.
+func FindRules(stylesPath string) (map[string]string, error) {
+	rules := map[string]string{}
+	styles, err := ioutil.ReadDir(stylesPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, style := range styles {
+		if !style.IsDir() {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(stylesPath, style.Name(), "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, fp := range files {
+			name := strings.TrimSuffix(filepath.Base(fp), ".yml")
+			rules[style.Name()+"."+name] = fp
+		}
+	}
+	return rules, nil
+}
+
+// ValidateRule reports every problem with the rule defined by `src` (the
+// contents of the file `path`): a missing or unknown extension point, unknown
+// keys, values of the wrong type, invalid regular expressions, and swap
+// keys with capture groups.
+func ValidateRule(path string, src []byte) []RuleError {
+	generic := map[string]interface{}{}
+	if err := yaml.Unmarshal(src, &generic); err != nil {
+		return []RuleError{yamlError(path, err)}
+	}
+
+	errs := []RuleError{}
+	report := func(key, text, msg string, args ...interface{}) {
+		errs = append(errs, RuleError{
+			Path: path, Line: findLine(src, key, text), Key: key,
+			Message: fmt.Sprintf(msg, args...)})
+	}
+
+	extends, ok := generic["extends"].(string)
+	if _, found := generic["extends"]; !found {
+		report("extends", "", "missing extension point")
+		return errs
+	} else if !ok || !core.StringInSlice(extends, extensionPoints) {
+		report("extends", "", "unknown extension point '%v'", generic["extends"])
+		return errs
+	} else if _, found := generic["message"]; !found {
+		report("message", "", "missing message")
+	}
+
+	if level, found := generic["level"]; found && !core.StringInSlice(fmt.Sprint(level), core.AlertLevels) {
+		report("level", "", "unknown level '%v' (expected one of %v)", level, core.AlertLevels)
+	}
+
+	known := knownKeys(definitionTypes[extends])
+	keys := []string{}
+	for key := range generic {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !known[strings.ToLower(key)] {
+			report(key, "", "unknown key for '%s'", extends)
+		}
+	}
+
+	def := reflect.New(definitionTypes[extends]).Interface()
+	if err := mapstructure.Decode(generic, def); err != nil {
+		if merr, ok := err.(*mapstructure.Error); ok {
+			sort.Strings(merr.Errors)
+			for _, msg := range merr.Errors {
+				key := "-"
+				if m := reErrorKey.FindStringSubmatch(msg); m != nil && m[1] != "" {
+					key, msg = strings.ToLower(m[1]), strings.TrimPrefix(msg, m[0])
+				}
+				report(key, "", "%s", msg)
+			}
+		} else {
+			report("-", "", "%s", err.Error())
+		}
+		return errs
+	}
+
+	for _, p := range patterns(def) {
+		if _, err := regexp.Compile(p.value); err != nil {
+			report(p.key, p.value, "invalid regex '%s': %s", p.value, err)
+		} else if p.swap && hasCaptureGroup(p.value) {
+			report(p.key, p.value, "'%s' has a group other than '(?:...)', so it's ignored", p.value)
+		}
+	}
+
+	return errs
+}
+
+// yamlError converts an error from parsing the YAML file `path` into a
+// RuleError.
+func yamlError(path string, err error) RuleError {
+	e := RuleError{Path: path, Key: "-", Message: err.Error()}
+	offset := 1 // syntax errors give the 0-based line the parser stopped on
+	if terr, ok := err.(*yaml.TypeError); ok && len(terr.Errors) > 0 {
+		e.Message, offset = terr.Errors[0], 0
+	}
+	if m := reYAMLLine.FindStringSubmatch(e.Message); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Line, e.Message = e.Line+offset, m[2]
+	}
+	return e
+}
+
+// knownKeys returns the (lowercased) keys that a definition of type `t`
+// accepts.
+func knownKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{"examples": true}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			for k := range knownKeys(field.Type) {
+				keys[k] = true
+			}
+		} else if field.Type.Kind() != reflect.Func {
+			keys[strings.ToLower(field.Name)] = true
+		}
+	}
+	// These are set by Vale itself.
+	delete(keys, "name")
+	delete(keys, "path")
+	return keys
+}
+
+// A pattern is a regular expression given by a rule.
+type pattern struct {
+	key   string // the key it was given by
+	value string
+	swap  bool // whether it's a substitution's swap key
+}
+
+// patterns returns the regular expressions given by the definition `def`.
+func patterns(def interface{}) []pattern {
+	found := []pattern{}
+	add := func(key string, values ...string) {
+		for _, v := range values {
+			found = append(found, pattern{key: key, value: v})
+		}
+	}
+
+	switch d := def.(type) {
+	case *Existence:
+		add("tokens", d.Tokens...)
+		if len(d.Raw) > 0 {
+			add("raw", strings.Join(d.Raw, ""))
+		}
+	case *Substitution:
+		for _, k := range sortedKeys(d.Swap) {
+			found = append(found, pattern{key: "swap", value: k, swap: true})
+		}
+	case *Occurrence:
+		add("token", d.Token)
+	case *Repetition:
+		add("tokens", d.Tokens...)
+	case *Consistency:
+		for _, k := range sortedKeys(d.Either) {
+			add("either", k, d.Either[k])
+		}
+	case *Conditional:
+		add("first", d.First)
+		add("second", d.Second)
+	case *Capitalization:
+		if !strings.HasPrefix(d.Match, "$") {
+			add("match", d.Match)
+		}
+	}
+	return found
+}
+
+// hasCaptureGroup determines if `re` contains a capturing group, which
+// substitution checks can't support (see `addSubstitutionCheck`).
+func hasCaptureGroup(re string) bool {
+	opens := strings.Count(re, "(")
+	return opens != strings.Count(re, "?:") && opens != strings.Count(re, `\(`)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// findLine returns the first line of `src` that defines `key` -- or, if
+// `text` is given, the first line after it that contains `text`.
+func findLine(src []byte, key, text string) int {
+	keyRE := regexp.MustCompile(`(?i)^\s*["']?` + regexp.QuoteMeta(key) + `["']?\s*:`)
+
+	line, keyLine := 0, 0
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		line++
+		if keyLine == 0 && keyRE.MatchString(scanner.Text()) {
+			keyLine = line
+			if text == "" {
+				return keyLine
+			}
+		} else if keyLine > 0 && strings.Contains(scanner.Text(), text) {
+			return line
+		}
+	}
+	return keyLine
+}
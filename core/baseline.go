@@ -0,0 +1,159 @@
+package core
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A Baseline maps the fingerprints of known alerts to the number of times
+// they occur.
+type Baseline map[string]int
+
+// A baselineEntry is a single alert as recorded in a baseline file.
+//
+// We include the check and path (which are also part of the fingerprint) so
+// that changes to the file are easy to review.
+type baselineEntry struct {
+	Check       string
+	Path        string
+	Fingerprint string
+}
+
+// WriteBaseline records the fingerprint of every alert in `linted` to the
+// file `path`.
+func WriteBaseline(path string, linted []*File) error {
+	entries := []baselineEntry{}
+	for _, f := range linted {
+		for _, a := range f.Alerts {
+			entries = append(entries, baselineEntry{
+				Check: a.Check, Path: baselinePath(f.Path),
+				Fingerprint: f.Fingerprint(a)})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		} else if entries[i].Check != entries[j].Check {
+			return entries[i].Check < entries[j].Check
+		}
+		return entries[i].Fingerprint < entries[j].Fingerprint
+	})
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// LoadBaseline reads a baseline file created by `WriteBaseline`.
+func LoadBaseline(path string) (Baseline, error) {
+	var entries []baselineEntry
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	} else if err = json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+
+	baseline := Baseline{}
+	for _, e := range entries {
+		baseline[e.Fingerprint]++
+	}
+	return baseline, nil
+}
+
+// Filter removes all of f's alerts that are recorded in b.
+//
+// If an alert occurs more often than it was recorded, only the extra
+// occurrences are kept.
+func (b Baseline) Filter(f *File) {
+	seen := map[string]int{}
+	alerts := []Alert{}
+	for _, a := range f.Alerts {
+		fp := f.Fingerprint(a)
+		if seen[fp] < b[fp] {
+			seen[fp]++
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+	f.Alerts = alerts
+}
+
+// Fingerprint identifies `a` by its check, f's path, its match, and the
+// sentence that contains it.
+//
+// We don't include its line or column so that unrelated edits to f don't
+// invalidate the fingerprint.
+func (f *File) Fingerprint(a Alert) string {
+	h := sha1.New()
+	for _, part := range []string{a.Check, baselinePath(f.Path), a.Match, f.sentenceAt(a)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sentenceAt returns the (whitespace-normalized) sentence that contains `a`,
+// falling back to its entire paragraph.
+//
+// We look for the sentence in the whole paragraph, with its line breaks
+// treated as spaces, so that re-wrapping the paragraph doesn't change it.
+func (f *File) sentenceAt(a Alert) string {
+	if a.Line < 1 || a.Line > len(f.Lines) {
+		return ""
+	}
+
+	first, last := a.Line-1, a.Line-1
+	for first > 0 && strings.TrimSpace(f.Lines[first-1]) != "" {
+		first--
+	}
+	for last < len(f.Lines)-1 && strings.TrimSpace(f.Lines[last+1]) != "" {
+		last++
+	}
+
+	line := f.Lines[a.Line-1]
+	col := runeOffset(line, a.Span[0]-1)
+	if col < 0 {
+		col = len(line)
+	}
+	col += len(strings.Join(f.Lines[first:a.Line-1], ""))
+
+	paragraph := strings.Replace(strings.Join(f.Lines[first:last+1], ""), "\n", " ", -1)
+	sentence := paragraph
+	from := 0
+	for _, s := range SentenceTokenizer.Tokenize(paragraph) {
+		idx := strings.Index(paragraph[from:], s)
+		if idx < 0 {
+			continue
+		}
+		start := from + idx
+		from = start + len(s)
+		if start <= col && col < from {
+			sentence = s
+			break
+		}
+	}
+	return strings.Join(strings.Fields(sentence), " ")
+}
+
+// baselinePath normalizes `path` so that fingerprints don't depend on how a
+// file was specified on the command line.
+func baselinePath(path string) string {
+	if filepath.IsAbs(path) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	config := NewConfig()
+	old := NewFileFromString("Intro.\n\nIt is very good. It is very bad.\n", "a.md", config)
+	edited := NewFileFromString("Intro.\n\nMore.\n\nIt is very good.  It is very bad.\n", "a.md", config)
+
+	good := Alert{Check: "vale.Editorializing", Line: 3, Span: []int{7, 10}, Match: "very"}
+	bad := Alert{Check: "vale.Editorializing", Line: 3, Span: []int{24, 27}, Match: "very"}
+	moved := Alert{Check: "vale.Editorializing", Line: 5, Span: []int{7, 10}, Match: "very"}
+
+	assert.Equal(t, old.Fingerprint(good), edited.Fingerprint(moved))
+	assert.NotEqual(t, old.Fingerprint(good), old.Fingerprint(bad))
+
+	other := NewFileFromString(old.Content, "b.md", config)
+	assert.NotEqual(t, old.Fingerprint(good), other.Fingerprint(good))
+}
+
+func TestFingerprintRewrapped(t *testing.T) {
+	config := NewConfig()
+	old := NewFileFromString("Intro.\n\nIt is very good. It is\nvery bad. It is fine.\n", "a.md", config)
+	rewrapped := NewFileFromString("Intro.\n\nIt is\nvery good. It is very bad.\nIt is fine.\n", "a.md", config)
+
+	good := Alert{Check: "vale.Editorializing", Line: 3, Span: []int{7, 10}, Match: "very"}
+	bad := Alert{Check: "vale.Editorializing", Line: 4, Span: []int{1, 4}, Match: "very"}
+	assert.Equal(t, "It is very bad.", old.sentenceAt(bad))
+
+	goodAfter := Alert{Check: "vale.Editorializing", Line: 4, Span: []int{1, 4}, Match: "very"}
+	badAfter := Alert{Check: "vale.Editorializing", Line: 4, Span: []int{18, 21}, Match: "very"}
+	assert.Equal(t, old.Fingerprint(good), rewrapped.Fingerprint(goodAfter))
+	assert.Equal(t, old.Fingerprint(bad), rewrapped.Fingerprint(badAfter))
+	assert.NotEqual(t, old.Fingerprint(good), old.Fingerprint(bad))
+}
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baseline")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	config := NewConfig()
+	f := NewFileFromString("It is very, very good.\n", "a.md", config)
+	a := Alert{Check: "vale.Editorializing", Line: 1, Span: []int{7, 10}, Match: "very"}
+	f.Alerts = []Alert{a}
+
+	path := filepath.Join(dir, "baseline.json")
+	assert.Nil(t, WriteBaseline(path, []*File{f}))
+
+	baseline, err := LoadBaseline(path)
+	assert.Nil(t, err)
+
+	// A second occurrence of the same alert isn't covered by the baseline.
+	b := Alert{Check: "vale.Editorializing", Line: 1, Span: []int{13, 16}, Match: "very"}
+	f.Alerts = []Alert{a, b}
+	baseline.Filter(f)
+	assert.Equal(t, []Alert{b}, f.Alerts)
+
+	_, err = LoadBaseline(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
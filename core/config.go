@@ -1,10 +1,15 @@
 package core
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gobwas/glob"
 	"github.com/mitchellh/go-homedir"
@@ -22,18 +27,23 @@ func (g Glob) Match(query string) bool {
 	return g.Pattern.Match(query) != g.Negated
 }
 
-// NewGlob creates a Glob from the string pat.
+// NewGlob creates a Glob from the string pat, panicking if it's invalid.
 func NewGlob(pat string) Glob {
-	negate := false
-	if strings.HasPrefix(pat, "!") {
-		pat = strings.TrimLeft(pat, "!")
-		negate = true
-	}
-	g, gerr := glob.Compile(pat)
+	g, gerr := CompileGlob(pat)
 	if !CheckError(gerr) {
 		panic(gerr)
 	}
-	return Glob{Pattern: g, Negated: negate}
+	return g
+}
+
+// CompileGlob creates a Glob from the string pat.
+func CompileGlob(pat string) (Glob, error) {
+	g, err := glob.Compile(strings.TrimLeft(pat, "!"))
+	if err != nil {
+		return Glob{}, fmt.Errorf("invalid glob '%s': %s", pat, err)
+	}
+	negate := strings.HasPrefix(pat, "!")
+	return Glob{Pattern: g, Negated: negate}, nil
 }
 
 // AlertLevels holds the possible values for "level" in an external rule.
@@ -50,11 +60,15 @@ var LevelToInt = map[string]int{
 type Config struct {
 	// General configuration
 	Checks         []string                   // All checks to load
+	Gates          Gates                      // Limits on the number of alerts
 	GBaseStyles    []string                   // Global base style
 	GChecks        map[string]bool            // Global checks
 	IgnoredScopes  []string                   // A list of HTML tags to ignore
+	IgnoreFiles    []string                   // Files listing paths to skip (e.g., .valeignore)
 	IgnorePatterns map[string][]string        // A list of regexp's indentifying sections to ignore
+	MaxFileSize    int64                      // Size (in bytes) above which we skip files
 	MinAlertLevel  int                        // Lowest alert level to display
+	Path           string                     // The config file we loaded, if any
 	RuleToLevel    map[string]string          // Single-rule level changes
 	SBaseStyles    map[string][]string        // Syntax-specific base styles
 	SChecks        map[string]map[string]bool // Syntax-specific checks
@@ -62,14 +76,23 @@ type Config struct {
 	WordTemplate   string                     // The template used in YAML -> regexp list conversions
 
 	// Command-line configuration
-	Output    string // (optional) output style ("line" or "CLI")
-	Wrap      bool   // (optional) wrap output when CLI style
-	NoExit    bool   // (optional) don't return a nonzero exit code on lint errors
-	Sorted    bool   // (optional) sort files by their name for output
-	Normalize bool   // (optional) replace each path separator with a slash ('/')
-	Simple    bool   // (optional) lint all files line-by-line
-	InExt     string // (optional) extension to associate with stdin
-	Relative  bool   // (optional) return relative paths
+	Output    string        // (optional) output style (e.g., "line" or "CLI")
+	Wrap      bool          // (optional) wrap output when CLI style
+	NoExit    bool          // (optional) don't return a nonzero exit code on lint errors
+	Sorted    bool          // (optional) sort files by their name for output
+	Normalize bool          // (optional) replace each path separator with a slash ('/')
+	Simple    bool          // (optional) lint all files line-by-line
+	InExt     string        // (optional) extension to associate with stdin
+	Relative  bool          // (optional) return relative paths
+	Strict    bool          // (optional) report every problem with our rules
+	Metrics   bool          // (optional) include document statistics in JSON output
+	CacheDir  string        // (optional) directory in which to cache results
+	Include   []string      // (optional) globs that files must match
+	Exclude   []string      // (optional) globs that files mustn't match
+	Profile   bool          // (optional) report where linting spends its time
+	Workers   int           // (optional) number of files to lint at once
+	Timeout   time.Duration // (optional) longest we'll spend linting a single file
+	WorkDir   string        // (optional) directory whose ignore files apply to the paths under it
 }
 
 // NewConfig initializes a Config.
@@ -82,6 +105,9 @@ func NewConfig() *Config {
 	cfg.GBaseStyles = []string{"vale"}
 	cfg.RuleToLevel = make(map[string]string)
 	cfg.IgnorePatterns = make(map[string][]string)
+	cfg.Gates = NewGates()
+	cfg.IgnoreFiles = []string{".valeignore"}
+	cfg.MaxFileSize = 10 << 20
 	return &cfg
 }
 
@@ -106,14 +132,26 @@ func validateLevel(key string, val string, cfg *Config) bool {
 	return true
 }
 
-// loadConfig loads the .vale file. It checks the current directory up to the
-// user's home directory, stopping on the first occurrence of a .vale or _vale
-// file.
-func loadConfig(names []string) (*ini.File, string, error) {
-	var configPath, dir string
+// loadConfig loads the .vale file. If `configPath` isn't given, it checks the
+// current directory up to the user's home directory, stopping on the first
+// occurrence of a .vale or _vale file.
+func loadConfig(configPath string, names []string) (*ini.File, string, string, error) {
+	var dir string
 	var iniFile *ini.File
 	var err error
 
+	if configPath != "" {
+		if !FileExists(configPath) || IsDir(configPath) {
+			return nil, "", "", fmt.Errorf("config file '%s' not found", configPath)
+		}
+		configPath, _ = filepath.Abs(configPath)
+		iniFile, err = ini.Load(configPath)
+		if err != nil {
+			err = fmt.Errorf("%s: %s", configPath, err)
+		}
+		return iniFile, configPath, filepath.Dir(configPath), err
+	}
+
 	count := 0
 	for configPath == "" && count < 6 {
 		if count == 0 {
@@ -135,18 +173,63 @@ func loadConfig(names []string) (*ini.File, string, error) {
 		configPath, _ = homedir.Dir()
 	}
 	iniFile, err = ini.Load(configPath)
-	return iniFile, dir, err
+	return iniFile, configPath, dir, err
 }
 
-// LoadConfig reads the .vale/_vale file.
-func LoadConfig() *Config {
+// LoadConfig reads the config file given by `configPath` (or
+// $VALE_CONFIG_PATH), falling back to the first .vale/_vale file we find.
+//
+// It's an error for an explicitly-given config file to be missing or for any
+// config file to be invalid. $VALE_STYLES_PATH, if set, overrides the file's
+// StylesPath.
+func LoadConfig(configPath string) (*Config, error) {
 	cfg := NewConfig()
 	names := []string{".vale", "_vale", "vale.ini", ".vale.ini", "_vale.ini"}
-	uCfg, path, err := loadConfig(names)
+
+	if configPath == "" {
+		configPath = os.Getenv("VALE_CONFIG_PATH")
+	}
+
+	uCfg, cfgPath, path, err := loadConfig(configPath, names)
+	if err != nil && configPath != "" {
+		return nil, err
+	} else if err != nil && !IsDir(cfgPath) {
+		// We found a config file (rather than falling back to the home
+		// directory), but couldn't parse it.
+		return nil, fmt.Errorf("%s: %s", cfgPath, err)
+	} else if err == nil {
+		cfg.Path = cfgPath
+		if err = readConfig(cfg, uCfg, path); err != nil {
+			return nil, fmt.Errorf("%s: %s", cfgPath, err)
+		}
+	}
+
+	if stylesPath := os.Getenv("VALE_STYLES_PATH"); stylesPath != "" {
+		cfg.StylesPath, _ = filepath.Abs(stylesPath)
+	}
+
+	return cfg, nil
+}
+
+// ParseConfig reads a config from the contents of a .vale.ini file, `src`.
+// Relative paths in it (e.g., StylesPath) are resolved against `dir`.
+//
+// Unlike LoadConfig, it doesn't search for a config file or consult any
+// environment variables.
+func ParseConfig(src []byte, dir string) (*Config, error) {
+	cfg := NewConfig()
+	uCfg, err := ini.Load(src)
 	if err != nil {
-		return cfg
+		return nil, err
+	} else if err = readConfig(cfg, uCfg, dir); err != nil {
+		return nil, err
 	}
+	return cfg, nil
+}
 
+// readConfig populates `cfg` from the config file `uCfg`, which is located in
+// the directory `path`.
+func readConfig(cfg *Config, uCfg *ini.File, path string) error {
 	core := uCfg.Section("")
 	global := uCfg.Section("*")
 
@@ -161,6 +244,16 @@ func LoadConfig() *Config {
 			cfg.IgnoredScopes = core.Key(k).Strings(",")
 		} else if k == "WordTemplate" {
 			cfg.WordTemplate = core.Key(k).String()
+		} else if k == "IgnoreFiles" {
+			cfg.IgnoreFiles = core.Key(k).Strings(",")
+		} else if k == "MaxFileSize" {
+			size, err := parseSize(core.Key(k).String())
+			if err != nil {
+				return err
+			}
+			cfg.MaxFileSize = size
+		} else if err := readGates(&cfg.Gates, k, core.Key(k).String()); err != nil {
+			return err
 		}
 	}
 
@@ -179,6 +272,8 @@ func LoadConfig() *Config {
 	for _, sec := range uCfg.SectionStrings() {
 		if sec == "*" || sec == "DEFAULT" {
 			continue
+		} else if _, err := glob.Compile(sec); err != nil {
+			return fmt.Errorf("invalid section '[%s]': %s", sec, err)
 		}
 		syntaxOpts := make(map[string]bool)
 		for _, k := range uCfg.Section(sec).KeyStrings() {
@@ -186,6 +281,11 @@ func LoadConfig() *Config {
 				cfg.SBaseStyles[sec] = uCfg.Section(sec).Key(k).Strings(",")
 			} else if k == "IgnorePatterns" {
 				cfg.IgnorePatterns[sec] = uCfg.Section(sec).Key(k).Strings(",")
+				for _, pat := range cfg.IgnorePatterns[sec] {
+					if _, err := regexp.Compile(pat); err != nil {
+						return fmt.Errorf("invalid IgnorePatterns in '[%s]': %s", sec, err)
+					}
+				}
 			} else {
 				syntaxOpts[k] = validateLevel(k, uCfg.Section(sec).Key(k).String(), cfg)
 				cfg.Checks = append(cfg.Checks, k)
@@ -193,6 +293,93 @@ func LoadConfig() *Config {
 		}
 		cfg.SChecks[sec] = syntaxOpts
 	}
+	return nil
+}
+
+// parseSize converts a size like "512", "64KB" or "10MB" into bytes.
+func parseSize(s string) (int64, error) {
+	n, unit := strings.TrimSpace(s), int64(1)
+	for suffix, size := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(strings.ToUpper(n), suffix) {
+			n, unit = strings.TrimSpace(n[:len(n)-2]), size
+			break
+		}
+	}
+	size, err := strconv.ParseInt(n, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid value for MaxFileSize: '%s'", s)
+	}
+	return size * unit, nil
+}
+
+// Sections returns the sections of cfg (e.g., "*" or "*.md") in which the
+// check `name` is enabled -- either explicitly or by one of their
+// BasedOnStyles.
+func (cfg *Config) Sections(name string) []string {
+	style := strings.Split(name, ".")[0]
+	sections := []string{}
+	if cfg.enabledIn(name, style, cfg.GBaseStyles, nil) {
+		sections = append(sections, "*")
+	}
+
+	names := []string{}
+	for sec := range cfg.SChecks {
+		names = append(names, sec)
+	}
+	for sec := range cfg.SBaseStyles {
+		if _, ok := cfg.SChecks[sec]; !ok {
+			names = append(names, sec)
+		}
+	}
+	sort.Strings(names)
+
+	for _, sec := range names {
+		base, ok := cfg.SBaseStyles[sec]
+		if !ok {
+			base = cfg.GBaseStyles
+		}
+		if cfg.enabledIn(name, style, base, cfg.SChecks[sec]) {
+			sections = append(sections, sec)
+		}
+	}
+	return sections
+}
 
-	return cfg
+// enabledIn determines if the check `name` would run for a file with the
+// given base styles and syntax-specific checks (see `Linter.lintText`).
+func (cfg *Config) enabledIn(name, style string, base []string, checks map[string]bool) bool {
+	if val, ok := checks[name]; ok {
+		return val
+	} else if val, ok := cfg.GChecks[name]; ok {
+		return val
+	}
+	return StringInSlice(style, base)
+}
+
+// ReloadConfig reads the config file given by `old.Path` (or the one we'd
+// find by default, if it's empty) again, keeping the command-line
+// configuration of `old`.
+func ReloadConfig(old *Config) (*Config, error) {
+	cfg, err := LoadConfig(old.Path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Output = old.Output
+	cfg.Wrap = old.Wrap
+	cfg.NoExit = old.NoExit
+	cfg.Sorted = old.Sorted
+	cfg.Normalize = old.Normalize
+	cfg.Simple = old.Simple
+	cfg.InExt = old.InExt
+	cfg.Relative = old.Relative
+	cfg.Strict = old.Strict
+	cfg.Metrics = old.Metrics
+	cfg.CacheDir = old.CacheDir
+	cfg.Include = old.Include
+	cfg.Exclude = old.Exclude
+	cfg.Profile = old.Profile
+	cfg.Workers = old.Workers
+	cfg.Timeout = old.Timeout
+	cfg.WorkDir = old.WorkDir
+	return cfg, nil
 }
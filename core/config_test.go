@@ -2,6 +2,9 @@ package core
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,3 +42,94 @@ func TestGlob(t *testing.T) {
 		}
 	}
 }
+
+func TestCompileGlob(t *testing.T) {
+	g, err := CompileGlob("!*.md")
+	assert.Nil(t, err)
+	assert.True(t, g.Negated)
+
+	_, err = CompileGlob("!*.[md")
+	assert.EqualError(t, err, "invalid glob '!*.[md': unexpected end of input")
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".vale.ini")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("MinAlertLevel = warning\n"), 0644))
+	cfg, err := LoadConfig(path)
+	assert.Nil(t, err)
+	cfg.Output, cfg.Include = "line", []string{"*.md"}
+
+	// We pick up the file's changes, but keep our command-line options.
+	assert.Nil(t, ioutil.WriteFile(path, []byte("MinAlertLevel = error\n"), 0644))
+	reloaded, err := ReloadConfig(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reloaded.MinAlertLevel)
+	assert.Equal(t, "line", reloaded.Output)
+	assert.Equal(t, []string{"*.md"}, reloaded.Include)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("[*\n"), 0644))
+	_, err = ReloadConfig(reloaded)
+	assert.NotNil(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "custom.ini")
+	ini := "StylesPath = styles\nMinAlertLevel = error\n\n[*]\nBasedOnStyles = vale\n"
+	assert.Nil(t, ioutil.WriteFile(path, []byte(ini), 0644))
+
+	cfg, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, path, cfg.Path)
+	assert.Equal(t, filepath.Join(dir, "styles"), cfg.StylesPath)
+	assert.Equal(t, 2, cfg.MinAlertLevel)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.ini"))
+	assert.NotNil(t, err)
+
+	// A config file we find is as much of an error as one we're given.
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	defer os.Chdir(cwd)
+	assert.Nil(t, os.Chdir(dir))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".vale.ini"), []byte("[*\n"), 0644))
+	_, err = LoadConfig("")
+	assert.NotNil(t, err)
+	assert.Nil(t, os.Remove(filepath.Join(dir, ".vale.ini")))
+
+	// An invalid section is an error up front, rather than for every file.
+	_, err = ParseConfig([]byte("[*.[md]\nBasedOnStyles = vale\n"), dir)
+	assert.EqualError(t, err, "invalid section '[*.[md]': unexpected end of input")
+	_, err = ParseConfig([]byte("[*.md]\nIgnorePatterns = (foo\n"), dir)
+	assert.NotNil(t, err)
+
+	os.Setenv("VALE_CONFIG_PATH", path)
+	os.Setenv("VALE_STYLES_PATH", dir)
+	defer os.Unsetenv("VALE_CONFIG_PATH")
+	defer os.Unsetenv("VALE_STYLES_PATH")
+
+	cfg, err = LoadConfig("")
+	assert.Nil(t, err)
+	assert.Equal(t, path, cfg.Path)
+	assert.Equal(t, dir, cfg.StylesPath)
+}
+
+func TestSections(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GBaseStyles = []string{"18F"}
+	cfg.SBaseStyles["*.rst"] = []string{"vale"}
+	cfg.SChecks["*.rst"] = map[string]bool{}
+	cfg.SChecks["*.md"] = map[string]bool{"18F.Clarity": false, "vale.Hedging": true}
+
+	assert.Equal(t, []string{"*"}, cfg.Sections("18F.Clarity"))
+	assert.Equal(t, []string{"*", "*.md"}, cfg.Sections("18F.Abbreviations"))
+	assert.Equal(t, []string{"*.md", "*.rst"}, cfg.Sections("vale.Hedging"))
+	assert.Equal(t, []string{}, cfg.Sections("write-good.Weasel"))
+}
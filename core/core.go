@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gobwas/glob"
@@ -23,11 +24,13 @@ type File struct {
 	ChkToCtx   map[string]string // maps a temporary context to a particular check
 	Comments   map[string]bool   // comment control statements
 	Content    string            // the raw file contents
-	Counts     map[string]int    // word counts
+	Counts     map[string]int    // document statistics (e.g., "words")
+	Errors     []Error           // problems that kept us from fully linting the file
 	Format     string            // 'code', 'markup' or 'prose'
 	Lines      []string          // the File's Content split into lines
 	NormedExt  string            // the normalized extension (see util/format.go)
 	Path       string            // the full path
+	Profile    *Profile          // (optional) records where linting spends its time
 	RealExt    string            // actual file extension
 	Scanner    *bufio.Scanner    // used by lintXXX functions
 	Sequences  []string          // tracks various info (e.g., defined abbreviations)
@@ -36,15 +39,16 @@ type File struct {
 
 // An Alert represents a potential error in prose.
 type Alert struct {
-	Check       string // the name of the check
-	Description string // why `Message` is meaningful
-	Line        int    // the source line
-	Link        string // reference material
-	Message     string // the output message
-	Severity    string // 'suggestion', 'warning', or 'error'
-	Span        []int  // the [begin, end] location within a line
-	Hide        bool   // should we hide this alert?
-	Match       string // the actual matched text
+	Check       string   // the name of the check
+	Description string   // why `Message` is meaningful
+	Line        int      // the source line
+	Link        string   // reference material
+	Message     string   // the output message
+	Severity    string   // 'suggestion', 'warning', or 'error'
+	Span        []int    // the [begin, end] location within a line
+	Hide        bool     // should we hide this alert?
+	Match       string   // the actual matched text
+	Suggestions []string // possible replacements for `Match`
 }
 
 // A Selector represents a named section of text.
@@ -95,25 +99,34 @@ func (a ByName) Less(i, j int) bool {
 
 // NewFile initilizes a File.
 func NewFile(src string, config *Config) *File {
-	var scanner *bufio.Scanner
-	var format, ext string
-	var fbytes []byte
-
 	if FileExists(src) {
-		fbytes, _ = ioutil.ReadFile(src)
-		scanner = bufio.NewScanner(bytes.NewReader(fbytes))
-		ext, format = FormatFromExt(src)
-	} else {
-		scanner = bufio.NewScanner(strings.NewReader(src))
-		ext, format = FormatFromExt(config.InExt)
-		fbytes = []byte(src)
-		src = "stdin" + ext
+		fbytes, err := ioutil.ReadFile(src)
+		file := newFile(src, fbytes, config)
+		if err != nil {
+			file.AddError(ErrorFile, err)
+		}
+		return file
 	}
+	ext, _ := FormatFromExt(config.InExt)
+	return newFile("stdin"+ext, []byte(src), config)
+}
+
+// NewFileFromString initilizes a File from `content`, treating it as though
+// it were located at `path` -- which determines both its format and the
+// sections of our config that apply to it.
+func NewFileFromString(content, path string, config *Config) *File {
+	return newFile(path, []byte(content), config)
+}
+
+func newFile(src string, fbytes []byte, config *Config) *File {
+	scanner := bufio.NewScanner(bytes.NewReader(fbytes))
+	ext, format := FormatFromExt(src)
 
+	// Our config's sections have already been validated (see readConfig).
 	baseStyles := config.GBaseStyles
 	for sec, styles := range config.SBaseStyles {
 		pat, err := glob.Compile(sec)
-		if CheckError(err) && pat.Match(src) {
+		if err == nil && pat.Match(src) {
 			baseStyles = styles
 			break
 		}
@@ -122,7 +135,7 @@ func NewFile(src string, config *Config) *File {
 	checks := make(map[string]bool)
 	for sec, smap := range config.SChecks {
 		pat, err := glob.Compile(sec)
-		if CheckError(err) && pat.Match(src) {
+		if err == nil && pat.Match(src) {
 			checks = smap
 			break
 		}
@@ -135,6 +148,7 @@ func NewFile(src string, config *Config) *File {
 		Path: src, NormedExt: ext, Format: format, RealExt: filepath.Ext(src),
 		BaseStyles: baseStyles, Checks: checks, Scanner: scanner, Lines: lines,
 		Comments: make(map[string]bool), Content: content,
+		Counts: make(map[string]int),
 	}
 
 	return &file
@@ -151,7 +165,8 @@ func (f *File) FindLoc(ctx, s string, pad, count int, loc []int) (int, []int) {
 	var length int
 	var lines []string
 
-	pos, substring := initialPosition(ctx, s[loc[0]:loc[1]], loc)
+	defer f.Profile.AddPhase(f.Path, PhaseLocation, time.Now())
+	pos, substring := f.initialPosition(ctx, s[loc[0]:loc[1]], loc)
 	if pos < 0 {
 		// Shouldn't happen ...
 		return pos, []int{0, 0}
@@ -239,5 +254,5 @@ var WordTokenizer = tokenize.NewRegexpTokenizer(
 // Tagger tags a sentence.
 //
 // We wait to initilize it until we need it since it's slow (~1s) and we may
-// not need it.
+// not need it; see LoadTagger.
 var Tagger *tag.PerceptronTagger
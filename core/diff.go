@@ -0,0 +1,185 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Diff maps the (absolute) path of each file in a unified diff to the line
+// ranges that it adds or changes.
+type Diff map[string][][]int
+
+var hunkHeaderRE = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseDiff reads the "new" side of the unified diff `r`, resolving its paths
+// relative to `root`.
+//
+// Only added lines are recorded, so diffs with or without context lines give
+// the same result.
+func ParseDiff(r io.Reader, root string) (Diff, error) {
+	var path string
+	var line, oldLines, newLines int
+
+	diff := Diff{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldLines > 0 || newLines > 0 {
+			// We're in a hunk, so `text` is a line of content.
+			if strings.HasPrefix(text, "+") {
+				diff.add(path, line)
+				line++
+				newLines--
+			} else if strings.HasPrefix(text, "-") {
+				oldLines--
+			} else if !strings.HasPrefix(text, `\`) {
+				line++
+				oldLines--
+				newLines--
+			}
+		} else if m := hunkHeaderRE.FindStringSubmatch(text); m != nil && path != "" {
+			line, _ = strconv.Atoi(m[2])
+			oldLines, newLines = hunkLength(m[1]), hunkLength(m[3])
+		} else if strings.HasPrefix(text, "+++ ") {
+			path = parseDiffPath(strings.TrimPrefix(text, "+++ "), root)
+		}
+	}
+	return diff, scanner.Err()
+}
+
+// hunkLength converts the (optional) line count of a hunk header.
+func hunkLength(count string) int {
+	if count == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(count)
+	return n
+}
+
+// add records `line` as changed, extending the last range if we can.
+func (d Diff) add(path string, line int) {
+	ranges := d[path]
+	if n := len(ranges); n > 0 && ranges[n-1][1] == line-1 {
+		ranges[n-1][1] = line
+	} else {
+		d[path] = append(ranges, []int{line, line})
+	}
+}
+
+// GitDiff returns the lines that have changed in the working tree since the
+// given git revision.
+func GitDiff(rev string) (Diff, error) {
+	var out, stderr bytes.Buffer
+
+	root, err := GitRoot()
+	if err != nil {
+		return nil, err
+	}
+	git := Which([]string{"git"})
+
+	// We set the prefixes explicitly since a user's config can change or
+	// remove them (e.g., diff.noprefix or diff.mnemonicPrefix).
+	cmd := exec.Command(
+		git, "diff", "--no-color", "--no-ext-diff", "--unified=0",
+		"--src-prefix=a/", "--dst-prefix=b/", rev, "--")
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return ParseDiff(&out, root)
+}
+
+// GitRoot returns the top-level directory of the git repository containing
+// the current directory.
+func GitRoot() (string, error) {
+	git := Which([]string{"git"})
+	if git == "" {
+		return "", errors.New("git not found")
+	}
+	root, err := exec.Command(git, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", errors.New("not a git repository")
+	}
+	return strings.TrimSpace(string(root)), nil
+}
+
+// Paths returns the files in d that still exist, sorted by name.
+//
+// Paths within the current directory are made relative to it.
+func (d Diff) Paths() []string {
+	cwd, _ := os.Getwd()
+	paths := []string{}
+	for path := range d {
+		if !FileExists(path) {
+			continue
+		} else if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Contains determines if the given line of `path` was added or changed.
+func (d Diff) Contains(path string, line int) bool {
+	abs, err := filepath.Abs(filepath.FromSlash(path))
+	if err != nil {
+		return false
+	}
+	for _, r := range d[abs] {
+		if InRange(line, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter removes all of f's alerts that aren't on a changed line.
+func (d Diff) Filter(f *File) {
+	alerts := []Alert{}
+	for _, a := range f.Alerts {
+		if d.Contains(f.Path, a.Line) {
+			alerts = append(alerts, a)
+		}
+	}
+	f.Alerts = alerts
+}
+
+// parseDiffPath converts a "+++" header into an absolute path, returning ""
+// for deleted files.
+func parseDiffPath(header, root string) string {
+	// Some tools (e.g., `diff -u`) append a tab and a timestamp to paths.
+	path := strings.SplitN(header, "\t", 2)[0]
+	if path == "/dev/null" {
+		return ""
+	} else if unquoted, err := strconv.Unquote(path); err == nil {
+		path = unquoted
+	}
+	path = strings.TrimPrefix(path, "b/")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	return path
+}
+
+// DiffFromStdin parses a unified diff from stdin, relative to the current
+// directory.
+func DiffFromStdin() (Diff, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiff(os.Stdin, cwd)
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDiff = `diff --git a/docs/a.md b/docs/a.md
+index 5d3b1a2..8f2c3d4 100644
+--- a/docs/a.md
++++ b/docs/a.md
+@@ -1,5 +1,7 @@
+ We utilize it.
+-
++Another line.
++
+ Old text to utilize.
+ 
+-More.
++New: we utilize more.
++++ Not a header.
+diff --git a/old.md b/old.md
+deleted file mode 100644
+--- a/old.md
++++ /dev/null
+@@ -1 +0,0 @@
+-Gone.
+--- b.md	2017-08-01 12:00:00
++++ b.md	2017-08-02 12:00:00
+@@ -3,0 +4 @@
++Added.
+`
+
+func TestParseDiff(t *testing.T) {
+	root, _ := filepath.Abs("testdata")
+	diff, err := ParseDiff(strings.NewReader(testDiff), root)
+	assert.Nil(t, err)
+
+	a := filepath.Join(root, "docs", "a.md")
+	b := filepath.Join(root, "b.md")
+	assert.Equal(t, Diff{a: {{2, 3}, {6, 7}}, b: {{4, 4}}}, diff)
+
+	assert.True(t, diff.Contains(filepath.Join("testdata", "docs", "a.md"), 6))
+	assert.False(t, diff.Contains(filepath.Join("testdata", "docs", "a.md"), 4))
+	assert.False(t, diff.Contains(filepath.Join("testdata", "old.md"), 1))
+}
+
+func TestParseDiffWithoutPrefixes(t *testing.T) {
+	noPrefix := strings.Replace(strings.Replace(testDiff, "a/", "", -1), "b/docs", "docs", -1)
+	root, _ := filepath.Abs("testdata")
+	diff, err := ParseDiff(strings.NewReader(noPrefix), root)
+	assert.Nil(t, err)
+
+	a := filepath.Join(root, "docs", "a.md")
+	b := filepath.Join(root, "b.md")
+	assert.Equal(t, Diff{a: {{2, 3}, {6, 7}}, b: {{4, 4}}}, diff)
+}
+
+func TestGitDiffIgnoresPrefixConfig(t *testing.T) {
+	if Which([]string{"git"}) == "" {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "diff")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	dir, _ = filepath.EvalSymlinks(dir)
+
+	cwd, err := os.Getwd()
+	assert.Nil(t, err)
+	defer os.Chdir(cwd)
+	assert.Nil(t, os.Chdir(dir))
+
+	git := func(args ...string) {
+		args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)
+		out, err := exec.Command("git", args...).CombinedOutput()
+		assert.Nil(t, err, string(out))
+	}
+	git("init", "-q")
+	assert.Nil(t, ioutil.WriteFile("a.md", []byte("One.\n"), 0644))
+	git("add", "a.md")
+	git("commit", "-q", "-m", "Add a.md")
+	assert.Nil(t, ioutil.WriteFile("a.md", []byte("One.\nTwo.\n"), 0644))
+
+	for _, option := range []string{"diff.noprefix", "diff.mnemonicPrefix"} {
+		git("config", option, "true")
+		diff, err := GitDiff("HEAD")
+		assert.Nil(t, err)
+		assert.Equal(t, Diff{filepath.Join(dir, "a.md"): {{2, 2}}}, diff, option)
+		git("config", "--unset", option)
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+// The kinds of Error.
+const (
+	ErrorCache     = "cache"     // we couldn't store a file's results
+	ErrorCanceled  = "canceled"  // linting was canceled
+	ErrorConverter = "converter" // a markup converter is missing or failed
+	ErrorFile      = "file"      // a file we couldn't read
+	ErrorRule      = "rule"      // a rule we couldn't load (e.g., invalid YAML)
+	ErrorSkipped   = "skipped"   // a file we didn't lint (e.g., it's too large)
+	ErrorTimeout   = "timeout"   // a file took longer than Config.Timeout
+)
+
+// An Error is a problem, other than an alert, that kept us from fully linting
+// a file or from loading our rules.
+type Error struct {
+	Kind    string // one of the Error* kinds
+	Path    string // the file involved, if any (for a rule, its YAML file)
+	Rule    string // the rule involved, if any (e.g., "18F.Clarity")
+	Message string
+}
+
+// NewError creates an Error of the given kind from `err`.
+func NewError(kind, path string, err error) Error {
+	return Error{Kind: kind, Path: path, Message: err.Error()}
+}
+
+func (e Error) Error() string {
+	if e.Path != "" {
+		return e.Path + ": " + e.Message
+	} else if e.Rule != "" {
+		// A built-in rule, which has no file.
+		return e.Rule + ": " + e.Message
+	}
+	return e.Message
+}
+
+// AddError records a problem that kept us from fully linting f.
+func (f *File) AddError(kind string, err error) {
+	f.Errors = append(f.Errors, NewError(kind, f.Path, err))
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An Edit represents a single replacement within a File's source.
+type Edit struct {
+	Alert Alert  // the alert that suggested this edit
+	Start int    // the byte offset at which the replaced text begins
+	End   int    // the byte offset at which the replaced text ends
+	Text  string // the replacement text
+}
+
+// Fix applies the unambiguous suggestions made by f's alerts to `src`, the
+// raw contents of f's source file.
+func (f *File) Fix(src []byte) ([]byte, []error) {
+	edits, errs := f.Edits(src)
+	fixed := []byte{}
+	last := 0
+	for _, e := range edits {
+		fixed = append(fixed, src[last:e.Start]...)
+		fixed = append(fixed, e.Text...)
+		last = e.End
+	}
+	fixed = append(fixed, src[last:]...)
+
+	return fixed, errs
+}
+
+// Edits maps the unambiguous suggestions made by f's alerts onto `src`, the
+// raw contents of f's source file, in order of their position.
+//
+// We refuse to make any edit that we can't map back to the source, that
+// falls within a code span, or that overlaps another edit -- the latter two
+// are returned as errors.
+func (f *File) Edits(src []byte) ([]Edit, []error) {
+	var errs []error
+
+	edits := []Edit{}
+	starts := lineOffsets(src)
+	for _, a := range f.Alerts {
+		if len(a.Suggestions) != 1 || a.Line < 1 || a.Line > len(starts) {
+			continue
+		}
+		edit, ok := f.findEdit(src, starts, a)
+		if !ok {
+			continue
+		} else if inCodeSpan(src, starts, f.NormedExt, edit.Start, a.Line) {
+			errs = append(errs, fmt.Errorf(
+				"%s:%d:%d: skipping '%s' (in a code span)",
+				f.Path, a.Line, a.Span[0], a.Match))
+			continue
+		}
+		edits = append(edits, edit)
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].Start != edits[j].Start {
+			return edits[i].Start < edits[j].Start
+		}
+		return edits[i].End < edits[j].End
+	})
+
+	var prev Edit
+
+	end := -1
+	accepted := []Edit{}
+	for idx, e := range edits {
+		if idx > 0 && prev.Start == e.Start && prev.End == e.End && prev.Text == e.Text {
+			continue // Two checks agree on the same edit.
+		} else if e.Start < end {
+			errs = append(errs, fmt.Errorf(
+				"%s:%d:%d: refusing overlapping edits from %s and %s",
+				f.Path, e.Alert.Line, e.Alert.Span[0], prev.Alert.Check,
+				e.Alert.Check))
+			if n := len(accepted); n > 0 && accepted[n-1].End > e.Start {
+				accepted = accepted[:n-1]
+			}
+		} else {
+			accepted = append(accepted, e)
+		}
+		end = Max(end, e.End)
+		prev = e
+	}
+
+	return accepted, errs
+}
+
+// findEdit maps the location of `a` back to the bytes of `src`.
+//
+// An Alert's span is given in runes, 1-based and inclusive, which is
+// unaffected by `PrepText` -- so we only need to find the start of its line.
+func (f *File) findEdit(src []byte, starts []int, a Alert) (Edit, bool) {
+	line := string(src[starts[a.Line-1]:lineEnd(src, starts, a.Line)])
+
+	begin, end := runeOffset(line, a.Span[0]-1), runeOffset(line, a.Span[1])
+	if begin < 0 || end < 0 || begin >= end {
+		return Edit{}, false
+	}
+
+	observed := line[begin:end]
+	trimmed := strings.TrimSpace(observed)
+	if !strings.EqualFold(trimmed, a.Match) {
+		// The alert doesn't point at what it matched, so we can't trust it.
+		return Edit{}, false
+	}
+	begin += strings.Index(observed, trimmed)
+
+	offset := starts[a.Line-1]
+	return Edit{
+		Alert: a,
+		Start: offset + begin,
+		End:   offset + begin + len(trimmed),
+		Text:  matchCase(trimmed, a.Suggestions[0]),
+	}, true
+}
+
+// matchCase applies the capitalization of `observed` to `repl` -- e.g.,
+// ("Utilize", "use") => "Use".
+func matchCase(observed, repl string) string {
+	first, _ := utf8.DecodeRuneInString(observed)
+	if utf8.RuneCountInString(observed) > 1 && observed == strings.ToUpper(observed) {
+		return strings.ToUpper(repl)
+	} else if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(repl)
+		return string(unicode.ToUpper(r)) + repl[size:]
+	}
+	return repl
+}
+
+// inCodeSpan determines if the byte offset `pos` on the given line falls
+// within an inline code span or a fenced code block.
+func inCodeSpan(src []byte, starts []int, ext string, pos, line int) bool {
+	delim, fence := "`", "```"
+	switch ext {
+	case ".rst":
+		delim, fence = "``", ""
+	case ".adoc":
+		fence = "----"
+	case ".md":
+		// Use the defaults.
+	default:
+		return false
+	}
+
+	before := string(src[starts[line-1]:pos])
+	if strings.Count(before, delim)%2 == 1 {
+		return true
+	}
+
+	fenced := false
+	for i := 0; fence != "" && i < line-1; i++ {
+		text := strings.TrimSpace(string(src[starts[i]:lineEnd(src, starts, i+1)]))
+		if strings.HasPrefix(text, fence) || (ext == ".md" && strings.HasPrefix(text, "~~~")) {
+			fenced = !fenced
+		}
+	}
+	return fenced
+}
+
+// lineOffsets returns the byte offset at which each line of `src` begins,
+// treating CRLF, CR, and LF as line endings (see `SplitLines`).
+func lineOffsets(src []byte) []int {
+	starts := []int{0}
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\r' && i+1 < len(src) && src[i+1] == '\n' {
+			i++
+		}
+		if src[i] == '\n' || src[i] == '\r' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// lineEnd returns the offset of the end of the given (1-based) line,
+// excluding its line ending.
+func lineEnd(src []byte, starts []int, line int) int {
+	end := len(src)
+	if line < len(starts) {
+		end = starts[line]
+	}
+	for end > starts[line-1] && (src[end-1] == '\n' || src[end-1] == '\r') {
+		end--
+	}
+	return end
+}
+
+// runeOffset converts a rune index into a byte offset within `s`.
+func runeOffset(s string, n int) int {
+	if n < 0 {
+		return -1
+	}
+	count := 0
+	for idx := range s {
+		if count == n {
+			return idx
+		}
+		count++
+	}
+	if count == n {
+		return len(s)
+	}
+	return -1
+}
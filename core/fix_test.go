@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func swap(line int, span []int, match, repl string) Alert {
+	return Alert{
+		Check: "Test.Swap", Line: line, Span: span, Match: match,
+		Suggestions: []string{repl}}
+}
+
+func TestFix(t *testing.T) {
+	src := "We utilize it.\r\nUtilize `utilize` and UTILIZE.\r\nThe café will utilize it.\n"
+	f := File{Path: "test.md", NormedExt: ".md", Alerts: []Alert{
+		swap(1, []int{4, 10}, "utilize", "use"),
+		swap(2, []int{1, 7}, "Utilize", "use"),
+		swap(2, []int{10, 16}, "utilize", "use"),
+		swap(2, []int{23, 29}, "UTILIZE", "use"),
+		swap(3, []int{15, 21}, "utilize", "use"),
+	}}
+
+	fixed, errs := f.Fix([]byte(src))
+	assert.Equal(t,
+		"We use it.\r\nUse `utilize` and USE.\r\nThe café will use it.\n",
+		string(fixed))
+	assert.Len(t, errs, 1)
+}
+
+func TestFixOverlap(t *testing.T) {
+	src := "This is a free gift.\n"
+	f := File{Path: "test.txt", NormedExt: ".txt", Alerts: []Alert{
+		swap(1, []int{11, 19}, "free gift", "gift"),
+		swap(1, []int{16, 19}, "gift", "present"),
+		swap(1, []int{1, 4}, "This", "that"),
+	}}
+
+	fixed, errs := f.Fix([]byte(src))
+	assert.Equal(t, "That is a free gift.\n", string(fixed))
+	assert.Len(t, errs, 1)
+}
+
+func TestFixMismatch(t *testing.T) {
+	src := "We utilize it.\n"
+	f := File{Path: "test.txt", NormedExt: ".txt", Alerts: []Alert{
+		swap(1, []int{1, 7}, "utilize", "use"),
+	}}
+
+	fixed, errs := f.Fix([]byte(src))
+	assert.Equal(t, src, string(fixed))
+	assert.Len(t, errs, 0)
+}
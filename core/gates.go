@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// reBudget matches an alert budget (e.g., "18F.* <= 5").
+var reBudget = regexp.MustCompile(`^\s*(\S+)\s*<=\s*(\d+)\s*$`)
+
+// Gates holds the limits, set in the config file, that a run must stay within
+// to succeed. A negative limit is unset.
+type Gates struct {
+	MaxErrors       int
+	MaxWarnings     int
+	MaxSuggestions  int
+	MaxAlertDensity float64 // alerts per 1,000 words
+	Budgets         []Budget
+}
+
+// A Budget limits the number of alerts raised by the checks matching
+// `Pattern` (e.g., "18F.*").
+type Budget struct {
+	Pattern string
+	Max     int
+	glob    glob.Glob
+}
+
+// A GateResult is the outcome of evaluating a single gate.
+type GateResult struct {
+	Gate   string  // the gate's name (e.g., "MaxWarnings" or "18F.*")
+	Actual float64 // the number of alerts (or their density)
+	Limit  float64
+}
+
+// Passed determines if the gate's limit was respected.
+func (r GateResult) Passed() bool {
+	return r.Actual <= r.Limit
+}
+
+// NewGates initializes a Gates with every limit unset.
+func NewGates() Gates {
+	return Gates{MaxErrors: -1, MaxWarnings: -1, MaxSuggestions: -1, MaxAlertDensity: -1}
+}
+
+// Enabled determines if any gate has been set.
+func (g Gates) Enabled() bool {
+	return g.MaxErrors >= 0 || g.MaxWarnings >= 0 || g.MaxSuggestions >= 0 ||
+		g.MaxAlertDensity >= 0 || len(g.Budgets) > 0
+}
+
+// ParseBudget creates a Budget from a string like "18F.* <= 5".
+func ParseBudget(s string) (Budget, error) {
+	m := reBudget.FindStringSubmatch(s)
+	if m == nil {
+		return Budget{}, fmt.Errorf("invalid alert budget '%s' (expected e.g. '18F.* <= 5')", s)
+	}
+	g, err := glob.Compile(m[1])
+	if err != nil {
+		return Budget{}, fmt.Errorf("invalid alert budget '%s': %s", s, err)
+	}
+	max, _ := strconv.Atoi(m[2])
+	return Budget{Pattern: m[1], Max: max, glob: g}, nil
+}
+
+// Check evaluates every gate that's been set against the alerts in `linted`.
+func (g Gates) Check(linted []*File) []GateResult {
+	levels := map[string]int{}
+	checks := map[string]int{}
+	alerts, words := 0, 0
+	for _, f := range linted {
+		alerts += len(f.Alerts)
+		words += f.Counts["words"]
+		for _, a := range f.Alerts {
+			levels[a.Severity]++
+			checks[a.Check]++
+		}
+	}
+
+	results := []GateResult{}
+	add := func(gate string, actual, limit float64) {
+		if limit >= 0 {
+			results = append(results, GateResult{Gate: gate, Actual: actual, Limit: limit})
+		}
+	}
+	add("MaxErrors", float64(levels["error"]), float64(g.MaxErrors))
+	add("MaxWarnings", float64(levels["warning"]), float64(g.MaxWarnings))
+	add("MaxSuggestions", float64(levels["suggestion"]), float64(g.MaxSuggestions))
+	add("MaxAlertDensity", alertDensity(alerts, words), g.MaxAlertDensity)
+
+	for _, b := range g.Budgets {
+		count := 0
+		for check, n := range checks {
+			if b.glob.Match(check) {
+				count += n
+			}
+		}
+		add(b.Pattern, float64(count), float64(b.Max))
+	}
+	return results
+}
+
+// readGates populates `gates` from the key `k` of the config file's default
+// section, if it's a gate.
+func readGates(gates *Gates, k, val string) error {
+	var err error
+	switch k {
+	case "MaxErrors":
+		gates.MaxErrors, err = strconv.Atoi(val)
+	case "MaxWarnings":
+		gates.MaxWarnings, err = strconv.Atoi(val)
+	case "MaxSuggestions":
+		gates.MaxSuggestions, err = strconv.Atoi(val)
+	case "MaxAlertDensity":
+		gates.MaxAlertDensity, err = strconv.ParseFloat(val, 64)
+	case "AlertBudgets":
+		for _, s := range strings.Split(val, ",") {
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			b, berr := ParseBudget(s)
+			if berr != nil {
+				return berr
+			}
+			gates.Budgets = append(gates.Budgets, b)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: '%s'", k, val)
+	}
+	return nil
+}
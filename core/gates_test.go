@@ -0,0 +1,90 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBudget(t *testing.T) {
+	b, err := ParseBudget(" 18F.* <= 5 ")
+	assert.Nil(t, err)
+	assert.Equal(t, "18F.*", b.Pattern)
+	assert.Equal(t, 5, b.Max)
+
+	for _, s := range []string{"18F.* < 5", "18F.*", "<= 5", "18F.* <= -1"} {
+		_, err = ParseBudget(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestGates(t *testing.T) {
+	config := NewConfig()
+	f := NewFileFromString("", "a.md", config)
+	f.Counts["words"] = 1000
+	f.Alerts = []Alert{
+		{Check: "18F.Clarity", Severity: "warning"},
+		{Check: "18F.Abbreviations", Severity: "error"},
+		{Check: "vale.Hedging", Severity: "warning"},
+	}
+
+	gates := NewGates()
+	assert.False(t, gates.Enabled())
+	assert.Equal(t, []GateResult{}, gates.Check([]*File{f}))
+
+	gates.MaxWarnings = 1
+	gates.MaxAlertDensity = 3
+	for _, s := range []string{"18F.* <= 1", "vale.Hedging <= 1"} {
+		b, err := ParseBudget(s)
+		assert.Nil(t, err)
+		gates.Budgets = append(gates.Budgets, b)
+	}
+	assert.True(t, gates.Enabled())
+
+	results := gates.Check([]*File{f})
+	assert.Equal(t, []GateResult{
+		{Gate: "MaxWarnings", Actual: 2, Limit: 1},
+		{Gate: "MaxAlertDensity", Actual: 3, Limit: 3},
+		{Gate: "18F.*", Actual: 2, Limit: 1},
+		{Gate: "vale.Hedging", Actual: 1, Limit: 1},
+	}, results)
+	assert.Equal(t, []bool{false, true, false, true}, []bool{
+		results[0].Passed(), results[1].Passed(), results[2].Passed(), results[3].Passed()})
+
+	// The density comes from the recorded word counts, not the content.
+	g := NewFileFromString("", "b.md", config)
+	g.Counts["words"] = 500
+	g.Alerts = []Alert{{Check: "vale.Hedging", Severity: "suggestion"}}
+	gates = NewGates()
+	gates.MaxAlertDensity = 2
+	assert.Equal(t, []GateResult{
+		{Gate: "MaxAlertDensity", Actual: 2.67, Limit: 2},
+	}, gates.Check([]*File{f, g}))
+	assert.Equal(t, []GateResult{
+		{Gate: "MaxAlertDensity", Actual: 0, Limit: 2},
+	}, gates.Check([]*File{}))
+}
+
+func TestLoadGates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gates")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".vale.ini")
+	ini := "MaxWarnings = 20\nMaxAlertDensity = 2.5\nAlertBudgets = 18F.* <= 5, vale.* <= 0\n"
+	assert.Nil(t, ioutil.WriteFile(path, []byte(ini), 0644))
+
+	cfg, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, cfg.Gates.MaxErrors)
+	assert.Equal(t, 20, cfg.Gates.MaxWarnings)
+	assert.Equal(t, 2.5, cfg.Gates.MaxAlertDensity)
+	assert.Equal(t, 2, len(cfg.Gates.Budgets))
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("MaxWarnings = many\n"), 0644))
+	_, err = LoadConfig(path)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// An ignoreRule is a single pattern from an ignore file.
+type ignoreRule struct {
+	base    string // the directory of the file that defined it
+	re      *regexp.Regexp
+	negate  bool // the pattern started with '!'
+	dirOnly bool // the pattern ended with '/'
+	rooted  bool // the pattern contained a '/', so it's relative to `base`
+}
+
+// An Ignorer decides which paths to skip according to ignore files (e.g.,
+// .valeignore or .gitignore), which follow .gitignore's syntax.
+//
+// Like Git, we read an ignore file from every directory we visit, and its
+// patterns only apply within that directory. The last matching pattern
+// decides whether a path is ignored.
+type Ignorer struct {
+	names []string
+	rules []ignoreRule
+	seen  map[string]bool
+}
+
+// NewIgnorer creates an Ignorer that reads the ignore files named `names`.
+func NewIgnorer(names []string) *Ignorer {
+	return &Ignorer{names: names, seen: make(map[string]bool)}
+}
+
+// LoadDir reads any ignore files in `dir`.
+func (ig *Ignorer) LoadDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil || ig.seen[dir] {
+		return err
+	}
+	ig.seen[dir] = true
+
+	for _, name := range ig.names {
+		f, err := os.Open(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if rule, ok := parseIgnoreRule(dir, scanner.Text()); ok {
+				ig.rules = append(ig.rules, rule)
+			}
+		}
+		f.Close()
+		if err = scanner.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadParents reads the ignore files of every directory from `top` down to
+// `path`'s parent, if `top` contains `path`.
+func (ig *Ignorer) LoadParents(path, top string) error {
+	top, err := filepath.Abs(top)
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(top, filepath.Dir(abs))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+
+	dir := top
+	if err = ig.LoadDir(dir); err != nil {
+		return err
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		if err = ig.LoadDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match determines if `path` -- a directory, if `isDir` is set -- is ignored.
+func (ig *Ignorer) Match(path string, isDir bool) bool {
+	if len(ig.rules) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	ignored := false
+	for _, rule := range ig.rules {
+		rel, err := filepath.Rel(rule.base, abs)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		} else if rule.dirOnly && !isDir {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if !rule.rooted {
+			rel = rel[strings.LastIndex(rel, "/")+1:]
+		}
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// parseIgnoreRule converts a line of an ignore file in `dir` into a rule.
+func parseIgnoreRule(dir, line string) (ignoreRule, bool) {
+	rule := ignoreRule{base: dir}
+
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	} else if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.rooted = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+
+	re, err := regexp.Compile("^" + ignoreToRegexp(line) + "$")
+	if err != nil {
+		return rule, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// ignoreToRegexp translates a .gitignore pattern into a regular expression.
+func ignoreToRegexp(pat string) string {
+	var re bytes.Buffer
+	for i := 0; i < len(pat); i++ {
+		c := pat[i]
+		switch {
+		case strings.HasPrefix(pat[i:], "**/") && (i == 0 || pat[i-1] == '/'):
+			// "**/" matches zero or more directories.
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pat[i:], "**") && i+2 == len(pat) && (i == 0 || pat[i-1] == '/'):
+			// A trailing "/**" matches everything inside.
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pat[i+1:], ']')
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := pat[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pat):
+			i++
+			re.WriteString(regexp.QuoteMeta(string(pat[i])))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return re.String()
+}
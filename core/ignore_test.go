@@ -0,0 +1,62 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnorer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ignore")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	rules := "# Comment\nnode_modules/\n/build\n*.min.md\n!keep.min.md\ndocs/**/gen\n\\#notes.md\n"
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, ".valeignore"), []byte(rules), 0644))
+	sub := filepath.Join(dir, "sub")
+	assert.Nil(t, os.MkdirAll(sub, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(sub, ".valeignore"), []byte("*.txt\n"), 0644))
+
+	ig := NewIgnorer([]string{".valeignore"})
+	assert.Nil(t, ig.LoadDir(dir))
+	assert.Nil(t, ig.LoadDir(sub))
+
+	for path, ignored := range map[string]bool{
+		"node_modules":              true,
+		"a/b/node_modules":          true,
+		"build":                     true,
+		"a/build":                   false,
+		"a/page.min.md":             true,
+		"a/keep.min.md":             false,
+		"docs/gen":                  true,
+		"docs/a/b/gen":              true,
+		"src/docs/gen":              false,
+		"#notes.md":                 true,
+		"README.md":                 false,
+		"sub/a.txt":                 true,
+		"a.txt":                     false,
+		"sub/node_modules":          true,
+		"sub/deeper/page.min.md":    true,
+		"sub/deeper/keep.min.md":    false,
+		"sub/deeper/not-ignored.md": false,
+	} {
+		isDir := filepath.Ext(path) == "" || path == "build"
+		assert.Equal(t, ignored, ig.Match(filepath.Join(dir, path), isDir), path)
+	}
+
+	// Directory-only patterns don't apply to files.
+	assert.False(t, ig.Match(filepath.Join(dir, "node_modules"), false))
+}
+
+func TestParseSize(t *testing.T) {
+	for s, expected := range map[string]int64{"512": 512, "64KB": 64 << 10, "10 mb": 10 << 20} {
+		size, err := parseSize(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, expected, size, s)
+	}
+	_, err := parseSize("big")
+	assert.NotNil(t, err)
+}
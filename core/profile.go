@@ -0,0 +1,165 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// The phases of linting, other than running rules, that a Profile times.
+const (
+	PhaseMarkdown    = "markdown"    // converting Markdown to HTML
+	PhaseRST         = "rst2html"    // the rst2html subprocess
+	PhaseAsciiDoc    = "asciidoctor" // the asciidoctor subprocess
+	PhaseTagging     = "CheckPOS"    // part-of-speech tagging
+	PhaseLocation    = "FindLoc"     // finding an alert's line and span
+	PhaseJaroWinkler = "JaroWinkler" // FindLoc's fuzzy fallback
+)
+
+// A Timing is the time spent in one rule, phase or file.
+type Timing struct {
+	Name  string
+	Calls int
+	Total time.Duration
+	Max   time.Duration
+	Worst string // the slowest rule (for files) or file (for rules)
+}
+
+// A Profile records where linting spends its time: in each rule, in each
+// phase (e.g., converting markup) and in each file.
+//
+// Its methods are safe to call concurrently and do nothing on a nil Profile,
+// so callers needn't check whether profiling is enabled.
+type Profile struct {
+	mu        sync.Mutex
+	rules     map[string]*Timing
+	phases    map[string]*Timing
+	files     map[string]*Timing
+	fileRules map[string]map[string]time.Duration
+}
+
+// NewProfile creates an empty Profile.
+func NewProfile() *Profile {
+	return &Profile{
+		rules:     make(map[string]*Timing),
+		phases:    make(map[string]*Timing),
+		files:     make(map[string]*Timing),
+		fileRules: make(map[string]map[string]time.Duration)}
+}
+
+// AddRule records a call to the rule `rule`, on `file`, that began at
+// `start`.
+func (p *Profile) AddRule(file, rule string, start time.Time) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	add(p.rules, rule, d)
+	if p.fileRules[file] == nil {
+		p.fileRules[file] = make(map[string]time.Duration)
+	}
+	p.fileRules[file][rule] += d
+}
+
+// AddPhase records time spent in `phase`, on `file`, since `start`.
+func (p *Profile) AddPhase(file, phase string, start time.Time) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	add(p.phases, phase, d)
+}
+
+// AddFile records the time spent linting `file` since `start`.
+func (p *Profile) AddFile(file string, start time.Time) {
+	if p == nil {
+		return
+	}
+	d := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	add(p.files, file, d)
+}
+
+// Rules returns the `n` rules with the greatest total time, each with the
+// file in which it spent the most time.
+func (p *Profile) Rules(n int) []Timing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rules := top(p.rules, n)
+	for i, r := range rules {
+		var worst time.Duration
+		for file, timings := range p.fileRules {
+			if d := timings[r.Name]; d > worst || (d == worst && d > 0 && file < rules[i].Worst) {
+				worst, rules[i].Worst = d, file
+			}
+		}
+	}
+	return rules
+}
+
+// Phases returns the time spent in each phase, sorted by total time.
+func (p *Profile) Phases() []Timing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return top(p.phases, len(p.phases))
+}
+
+// Files returns the `n` files with the greatest total time, each with its
+// slowest rule.
+func (p *Profile) Files(n int) []Timing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	files := top(p.files, n)
+	for i, f := range files {
+		var worst time.Duration
+		for rule, d := range p.fileRules[f.Name] {
+			if d > worst || (d == worst && rule < files[i].Worst) {
+				worst, files[i].Worst = d, rule
+			}
+		}
+	}
+	return files
+}
+
+func add(timings map[string]*Timing, name string, d time.Duration) {
+	t, ok := timings[name]
+	if !ok {
+		t = &Timing{Name: name}
+		timings[name] = t
+	}
+	t.Calls++
+	t.Total += d
+	if d > t.Max {
+		t.Max = d
+	}
+}
+
+// top returns (copies of) the `n` entries of `timings` with the greatest
+// total time.
+func top(timings map[string]*Timing, n int) []Timing {
+	sorted := make([]Timing, 0, len(timings))
+	for _, t := range timings {
+		sorted = append(sorted, *t)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if n >= 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
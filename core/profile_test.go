@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	var disabled *Profile
+	disabled.AddRule("a.md", "vale.Hedging", time.Now())
+
+	p := NewProfile()
+	now := time.Now()
+	p.AddRule("a.md", "vale.Hedging", now.Add(-3*time.Second))
+	p.AddRule("b.md", "vale.Hedging", now.Add(-time.Second))
+	p.AddRule("b.md", "vale.Editorializing", now.Add(-2*time.Second))
+	p.AddPhase("a.md", PhaseMarkdown, now.Add(-time.Second))
+	p.AddFile("a.md", now.Add(-4*time.Second))
+	p.AddFile("b.md", now.Add(-5*time.Second))
+
+	rules := p.Rules(1)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "vale.Hedging", rules[0].Name)
+	assert.Equal(t, 2, rules[0].Calls)
+	assert.Equal(t, "a.md", rules[0].Worst)
+	assert.True(t, rules[0].Max >= 3*time.Second && rules[0].Total >= 4*time.Second)
+
+	files := p.Files(10)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "b.md", files[0].Name)
+	assert.Equal(t, "vale.Editorializing", files[0].Worst)
+	assert.Equal(t, "vale.Hedging", files[1].Worst)
+
+	phases := p.Phases()
+	assert.Len(t, phases, 1)
+	assert.Equal(t, PhaseMarkdown, phases[0].Name)
+}
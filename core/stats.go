@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/jdkato/prose/summarize"
+)
+
+// wordsPerMinute is the reading speed we assume when estimating reading
+// time.
+const wordsPerMinute = 200
+
+// Stats summarizes the content of one or more Files.
+type Stats struct {
+	Words        int
+	Sentences    int
+	Paragraphs   int
+	Headings     int
+	Alerts       int
+	AlertDensity float64 // alerts per 1,000 words
+	ReadingTime  float64 // in minutes
+	Readability  Readability
+}
+
+// Readability holds the readability metrics computed by `summarize`.
+//
+// FleschReadingEase and DaleChall are scores; the rest are grade levels.
+type Readability struct {
+	AutomatedReadability float64
+	ColemanLiau          float64
+	DaleChall            float64
+	FleschKincaid        float64
+	FleschReadingEase    float64
+	GunningFog           float64
+	SMOG                 float64
+}
+
+// Stats computes f's statistics from the counts recorded while linting it.
+func (f *File) Stats() Stats {
+	return TotalStats([]*File{f})
+}
+
+// TotalStats computes the statistics of `files` as a whole.
+func TotalStats(files []*File) Stats {
+	var prose bytes.Buffer
+
+	stats := Stats{}
+	for _, f := range files {
+		stats.Words += f.Counts["words"]
+		stats.Sentences += f.Counts["sentences"]
+		stats.Paragraphs += f.Counts["paragraphs"]
+		stats.Headings += f.Counts["headings"]
+		stats.Alerts += len(f.Alerts)
+		prose.WriteString(f.prose() + "\n\n")
+	}
+
+	if stats.Words > 0 {
+		stats.AlertDensity = alertDensity(stats.Alerts, stats.Words)
+		stats.ReadingTime = round(float64(stats.Words) / wordsPerMinute)
+	}
+
+	doc := summarize.NewDocument(prose.String())
+	if stats.Words > 0 && doc.NumWords > 0 && doc.NumSentences > 0 {
+		stats.Readability = Readability{
+			AutomatedReadability: round(doc.AutomatedReadability()),
+			ColemanLiau:          round(doc.ColemanLiau()),
+			DaleChall:            round(doc.DaleChall()),
+			FleschKincaid:        round(doc.FleschKincaid()),
+			FleschReadingEase:    round(doc.FleschReadingEase()),
+			GunningFog:           round(doc.GunningFog()),
+			SMOG:                 round(doc.SMOG()),
+		}
+	}
+
+	return stats
+}
+
+// prose returns the part of f's content that we use to assess readability:
+// the summary built while linting markup (which excludes headings, lists and
+// tables) or, for other formats, the entire file.
+func (f *File) prose() string {
+	if f.Format == "markup" && f.Summary.Len() > 0 {
+		return f.Summary.String()
+	} else if f.Format == "code" {
+		return ""
+	}
+	return f.Content
+}
+
+// alertDensity computes the number of alerts per 1,000 words.
+func alertDensity(alerts, words int) float64 {
+	if words == 0 {
+		return 0
+	}
+	return round(1000 * float64(alerts) / float64(words))
+}
+
+// round rounds `n` to two decimal places.
+func round(n float64) float64 {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0
+	}
+	return math.Floor(n*100+0.5) / 100
+}
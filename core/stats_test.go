@@ -0,0 +1,31 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotalStats(t *testing.T) {
+	config := NewConfig()
+	a := NewFileFromString("It is good. It is very good.\n", "a.txt", config)
+	a.Counts = map[string]int{"words": 300, "sentences": 20, "paragraphs": 4}
+	a.Alerts = []Alert{{Check: "vale.Editorializing"}}
+
+	b := NewFileFromString("", "b.txt", config)
+	b.Counts = map[string]int{"words": 100, "headings": 2}
+	b.Alerts = []Alert{{Check: "vale.Hedging"}, {Check: "vale.Hedging"}}
+
+	stats := TotalStats([]*File{a, b})
+	assert.Equal(t, 400, stats.Words)
+	assert.Equal(t, 20, stats.Sentences)
+	assert.Equal(t, 4, stats.Paragraphs)
+	assert.Equal(t, 2, stats.Headings)
+	assert.Equal(t, 3, stats.Alerts)
+	assert.Equal(t, 7.5, stats.AlertDensity)
+	assert.Equal(t, 2.0, stats.ReadingTime)
+	assert.NotZero(t, stats.Readability.FleschReadingEase)
+
+	// There's no text to assess b's readability with.
+	assert.Equal(t, Readability{}, b.Stats().Readability)
+}
@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -55,13 +57,24 @@ func SlicesEqual(a, b []string) bool {
 	return true
 }
 
+// taggerOnce guards the initialization of Tagger, which may be requested by
+// many goroutines at once.
+var taggerOnce sync.Once
+
+// LoadTagger initilizes our part-of-speech tagger, if needed. It's safe to
+// call concurrently.
+func LoadTagger() {
+	taggerOnce.Do(func() {
+		if Tagger == nil {
+			Tagger = tag.NewPerceptronTagger()
+		}
+	})
+}
+
 // CheckPOS determines if a match (as found by an extension point) also matches
 // the expected part-of-speech in text.
 func CheckPOS(loc []int, expected, text string) bool {
-	// Initilize our tagger, if needed.
-	if Tagger == nil {
-		Tagger = tag.NewPerceptronTagger()
-	}
+	LoadTagger()
 
 	pos := 1
 	observed := []string{}
@@ -157,12 +170,13 @@ func JaroWinkler(ctx, sub string) (int, string) {
 
 // initialPosition calculates the position of a match (given by the location in
 // the reference document, `loc`) in the source document (`ctx`).
-func initialPosition(ctx, sub string, loc []int) (int, string) {
+func (f *File) initialPosition(ctx, sub string, loc []int) (int, string) {
 	idx := strings.Index(ctx, sub)
 	if idx < 0 {
 		// Fall back to the JaroWinkler distance. This should only happen if
 		// we're in a scope that contains inline markup (e.g., a sentence with
 		// code spans).
+		defer f.Profile.AddPhase(f.Path, PhaseJaroWinkler, time.Now())
 		return JaroWinkler(ctx, sub)
 	}
 	pat := `(?:^|\b|_)` + regexp.QuoteMeta(sub) + `(?:\b|_|$)`
@@ -0,0 +1,22 @@
+Feature: Baseline
+  Background:
+    Given a file named "test.md" with:
+    """
+    This is a very old sentence.
+
+    """
+
+  Scenario: Hide the alerts recorded in a baseline
+    When I run vale "baseline create --file=baseline.json test.md"
+    And I append to "test.md" with:
+    """
+
+    This is a very new sentence.
+
+    """
+    And I run vale "--baseline=baseline.json test.md"
+    Then the output should contain exactly:
+    """
+    test.md:4:11:vale.Editorializing:Consider removing 'very'
+    """
+    And the exit status should be 0
@@ -0,0 +1,23 @@
+Feature: Cache
+  Background:
+    Given a file named "docs/test.md" with:
+    """
+    It is very good.
+
+    """
+
+  Scenario: Reuse cached results
+    When I run vale "--cache-dir=.vale-cache docs"
+    And I run vale "--cache-dir=.vale-cache docs"
+    Then the output should contain "docs/test.md:1:7:vale.Editorializing:Consider removing 'very'"
+    And a directory named ".vale-cache" should exist
+
+  Scenario: Lint a changed file again
+    When I run vale "--cache-dir=.vale-cache docs"
+    And a file named "docs/test.md" with:
+    """
+    It is very, very good.
+
+    """
+    And I run vale "--cache-dir=.vale-cache docs"
+    Then the output should contain "docs/test.md:1:13:vale.Editorializing:Consider removing 'very'"
@@ -264,3 +264,65 @@ Feature: Config
     test.py:1:37:write-good.Weasal:'Very' is a weasal word!
     """
     And the exit status should be 1
+
+  Scenario: Load a config file given by --config
+    Given a file named "ci/strict.ini" with:
+    """
+    MinAlertLevel = error
+
+    [*]
+    BasedOnStyles = vale
+    """
+    And a file named ".vale" with:
+    """
+    MinAlertLevel = warning
+
+    [*]
+    BasedOnStyles = vale
+    """
+    When I run vale "--config=ci/strict.ini test.md"
+    Then the output should contain exactly:
+    """
+    """
+    And the exit status should be 0
+
+  Scenario: Report a missing config file given by --config
+    When I run vale "--config=missing.ini test.md"
+    Then the output should contain "config file 'missing.ini' not found"
+    And the exit status should be 2
+
+  Scenario: Report an invalid config file
+    Given a file named ".vale.ini" with:
+    """
+    [*
+    BasedOnStyles = vale
+    """
+    When I run vale "test.md"
+    Then the stderr should contain "unclosed section"
+    And the exit status should be 2
+
+  Scenario: List the loaded rules
+    Given a file named ".vale.ini" with:
+    """
+    StylesPath = styles
+
+    [*]
+    BasedOnStyles = vale
+
+    [*.md]
+    Test.Utilize = YES
+
+    """
+    And a file named "styles/Test/Utilize.yml" with:
+    """
+    extends: substitution
+    message: "Use '%s' instead of '%s'."
+    swap:
+      utilize: use
+
+    """
+    When I run vale "ls-rules"
+    Then the output should contain "NAME                 STYLE  EXTENDS       LEVEL       SCOPE  SECTIONS  FILE"
+    And the output should contain "Test.Utilize         Test   substitution  warning     text   *.md"
+    And the output should contain "vale.Hedging         vale   existence     warning     text"
+    And the exit status should be 0
@@ -0,0 +1,28 @@
+Feature: Diff
+  Background:
+    Given a file named "test.md" with:
+    """
+    This is a very old sentence.
+
+    This is a very new sentence.
+
+    """
+    And a file named "test.diff" with:
+    """
+    --- a/test.md
+    +++ b/test.md
+    @@ -1,2 +1,4 @@
+     This is a very old sentence.
+    +
+    +This is a very new sentence.
+     
+
+    """
+
+  Scenario: Only report alerts on changed lines
+    When I run vale with diff "test.diff"
+    Then the output should contain exactly:
+    """
+    test.md:3:11:vale.Editorializing:Consider removing 'very'
+    """
+    And the exit status should be 0
@@ -0,0 +1,37 @@
+Feature: Errors
+  Background:
+    Given a file named "test.md" with:
+    """
+    It is very good.
+
+    """
+    And a file named ".vale.ini" with:
+    """
+    StylesPath = styles
+
+    [*]
+    BasedOnStyles = vale, Bad
+
+    """
+    And a file named "styles/Bad/Regex.yml" with:
+    """
+    extends: existence
+    message: "Found '%s'"
+    tokens:
+      - '(unclosed'
+
+    """
+
+  Scenario: Report a broken rule on stderr
+    When I run vale "test.md"
+    Then the output should contain "test.md:1:7:vale.Editorializing:Consider removing 'very'"
+    And the stderr should contain "Regex.yml: error parsing regexp"
+    And the exit status should be 2
+
+  Scenario: Include a broken rule in JSON output
+    When I run vale "--output=JSON test.md"
+    Then the output should contain "\"errors\": ["
+    And the output should contain "\"Kind\": \"rule\""
+    And the output should contain "\"Rule\": \"Bad.Regex\""
+    And the stderr should not contain "Regex.yml"
+    And the exit status should be 2
@@ -0,0 +1,36 @@
+Feature: Fix
+  Background:
+    Given a file named "test.md" with:
+    """
+    We utilize the `utilize` command. Utilize it often.
+
+    """
+    And a file named ".vale" with:
+    """
+    StylesPath = ../../styles/
+    MinAlertLevel = warning
+
+    [*]
+    BasedOnStyles = PlainLanguage
+    """
+
+  Scenario: Print a diff of the suggested fixes
+    When I run vale "fix --dry-run test.md"
+    Then the output should contain exactly:
+    """
+    --- a/test.md
+    +++ b/test.md
+    @@ -1 +1 @@
+    -We utilize the `utilize` command. Utilize it often.
+    +We use the `utilize` command. Use it often.
+    """
+    And the exit status should be 0
+
+  Scenario: Apply the suggested fixes in place
+    When I run vale "fix test.md"
+    Then the file "test.md" should contain exactly:
+    """
+    We use the `utilize` command. Use it often.
+
+    """
+    And the exit status should be 0
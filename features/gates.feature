@@ -0,0 +1,47 @@
+Feature: Quality gates
+  Background:
+    Given a file named "test.md" with:
+    """
+    It is very, very good.
+
+    """
+
+  Scenario: Stay within our budgets
+    Given a file named ".vale.ini" with:
+    """
+    MaxWarnings = 5
+    AlertBudgets = vale.* <= 2
+
+    [*]
+    BasedOnStyles = vale
+    """
+    When I run vale "test.md"
+    Then the output should contain "✔ MaxWarnings: 2 (limit 5)"
+    And the output should contain "✔ 2 quality gates passed."
+    And the exit status should be 0
+
+  Scenario: Exceed our budgets
+    Given a file named ".vale.ini" with:
+    """
+    MaxWarnings = 1
+    MaxAlertDensity = 100
+    AlertBudgets = vale.* <= 2
+
+    [*]
+    BasedOnStyles = vale
+    """
+    When I run vale "test.md"
+    Then the output should contain "test.md:1:7:vale.Editorializing:Consider removing 'very'"
+    And the output should contain "✖ MaxWarnings: 2 (limit 1)"
+    And the output should contain "✖ MaxAlertDensity: 400 (limit 100)"
+    And the output should contain "✖ 2 of 3 quality gates failed."
+    And the exit status should be 1
+
+  Scenario: Use an invalid budget
+    Given a file named ".vale.ini" with:
+    """
+    AlertBudgets = vale.* < 2
+    """
+    When I run vale "test.md"
+    Then the output should contain "invalid alert budget 'vale.* < 2'"
+    And the exit status should be 1
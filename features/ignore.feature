@@ -0,0 +1,53 @@
+Feature: Ignore
+  Background:
+    Given a file named "docs/test.md" with:
+    """
+    It is very good.
+
+    """
+    And a file named "docs/node_modules/pkg/README.md" with:
+    """
+    It is very good.
+
+    """
+    And a file named "docs/build/out.md" with:
+    """
+    It is very good.
+
+    """
+
+  Scenario: Skip paths listed in .valeignore
+    Given a file named "docs/.valeignore" with:
+    """
+    node_modules/
+    /build
+
+    """
+    When I run vale "docs"
+    Then the output should contain "docs/test.md:1:7:vale.Editorializing:Consider removing 'very'"
+    And the output should not contain "node_modules"
+    And the output should not contain "build"
+
+  Scenario: Read .gitignore files
+    Given a file named ".vale.ini" with:
+    """
+    IgnoreFiles = .valeignore, .gitignore
+
+    [*]
+    BasedOnStyles = vale
+
+    """
+    And a file named "docs/.gitignore" with:
+    """
+    build/
+
+    """
+    When I run vale "docs"
+    Then the output should contain "docs/node_modules/pkg/README.md"
+    And the output should not contain "docs/build/out.md"
+
+  Scenario: Filter files with --include and --exclude
+    When I run vale "--exclude=**/node_modules/** docs"
+    Then the output should contain "docs/test.md"
+    And the output should contain "docs/build/out.md"
+    And the output should not contain "node_modules"
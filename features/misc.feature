@@ -38,3 +38,17 @@ Feature: Misc
     test.md:1:39:vale.Editorializing:Consider removing 'very'
     test.md:1:57:vale.Editorializing:Consider removing 'very'
     """
+
+  Scenario: Lint one file at a time
+    When I run vale "--workers=1 test.md"
+    Then the output should contain "test.md:1:9:vale.Editorializing:Consider removing 'very'"
+
+  Scenario: Time out
+    When I run vale "--timeout=1ns test.md"
+    Then the stderr should contain "test.md: timed out after 1ns"
+    And the exit status should be 2
+
+  Scenario: Use an invalid include pattern
+    When I run vale "--include=[ab test.md"
+    Then the stderr should contain "invalid glob '[ab': unexpected end of input"
+    And the exit status should be 2
@@ -0,0 +1,166 @@
+Feature: Output
+  Scenario: Lint a file with SARIF output
+    When I lint "--output=SARIF test.txt"
+    Then the output should contain:
+    """
+              "ruleId": "vale.Annotations",
+              "ruleIndex": 0,
+              "level": "note",
+              "message": {
+                "text": "'NOTE' left in text"
+              },
+              "locations": [
+                {
+                  "physicalLocation": {
+                    "artifactLocation": {
+                      "uri": "fixtures/formats/test.txt",
+                      "uriBaseId": "SRCROOT"
+                    },
+                    "region": {
+                      "startLine": 1,
+                      "startColumn": 27,
+                      "endColumn": 31
+                    }
+                  }
+                }
+              ]
+    """
+    And the output should contain "\"originalUriBaseIds\": {"
+    And the exit status should be 0
+
+  Scenario: Lint a file with JUnit output
+    When I lint "--output=JUnit test.txt"
+    Then the output should contain exactly:
+    """
+    <?xml version="1.0" encoding="UTF-8"?>
+    <testsuites name="vale" tests="1" failures="0" skipped="1">
+      <testsuite name="vale" tests="1" failures="0" skipped="1">
+        <testcase classname="vale" name="test.txt">
+          <skipped message="&#39;NOTE&#39; left in text">test.txt:1:27: &#39;NOTE&#39; left in text (suggestion)</skipped>
+          <skipped message="&#39;XXX&#39; left in text">test.txt:4:12: &#39;XXX&#39; left in text (suggestion)</skipped>
+          <skipped message="&#39;TODO&#39; left in text">test.txt:4:66: &#39;TODO&#39; left in text (suggestion)</skipped>
+        </testcase>
+      </testsuite>
+    </testsuites>
+    """
+    And the exit status should be 0
+
+  Scenario: Report errors and clean files with JUnit output
+    Given a file named ".vale.ini" with:
+    """
+    [*]
+    BasedOnStyles = vale
+    vale.Editorializing = error
+
+    """
+    And a file named "a.md" with:
+    """
+    It is very good.
+
+    """
+    And a file named "b.md" with:
+    """
+    It is good.
+
+    """
+    When I run vale "--output=JUnit a.md b.md"
+    Then the output should contain exactly:
+    """
+    <?xml version="1.0" encoding="UTF-8"?>
+    <testsuites name="vale" tests="2" failures="1" skipped="0">
+      <testsuite name="vale" tests="2" failures="1" skipped="0">
+        <testcase classname="vale" name="a.md">
+          <failure message="Consider removing &#39;very&#39;" type="vale.Editorializing">a.md:1:7: Consider removing &#39;very&#39; (error)</failure>
+        </testcase>
+        <testcase classname="vale" name="b.md"></testcase>
+      </testsuite>
+    </testsuites>
+    """
+    And the exit status should be 1
+
+  Scenario: Lint a file with Checkstyle output
+    When I lint "--output=checkstyle test.txt"
+    Then the output should contain exactly:
+    """
+    <?xml version="1.0" encoding="UTF-8"?>
+    <checkstyle version="5.0">
+      <file name="test.txt">
+        <error line="1" column="27" severity="info" message="&#39;NOTE&#39; left in text" source="vale.Annotations"></error>
+        <error line="4" column="12" severity="info" message="&#39;XXX&#39; left in text" source="vale.Annotations"></error>
+        <error line="4" column="66" severity="info" message="&#39;TODO&#39; left in text" source="vale.Annotations"></error>
+      </file>
+    </checkstyle>
+    """
+    And the exit status should be 0
+
+  Scenario: Lint a file with HTML output
+    When I lint "--output=html test.txt"
+    Then the output should contain "<title>Vale report</title>"
+    And the output should contain "<summary>test.txt (3 alerts)</summary>"
+    And the output should contain:
+    """
+    <mark class="suggestion" data-ids="0">NOTE<span class="tip">
+    """
+    And the output should contain "<option>vale.Annotations</option>"
+    And the exit status should be 0
+
+  Scenario: Lint a file with a template
+    When I lint "--output=../templates/line.tmpl test.txt"
+    Then the output should contain exactly:
+    """
+    test.txt:1:27:vale.Annotations:'NOTE' left in text
+    test.txt:4:12:vale.Annotations:'XXX' left in text
+    test.txt:4:66:vale.Annotations:'TODO' left in text
+    """
+    And the exit status should be 0
+
+  Scenario: Use the JSON helpers in a template
+    Given a file named "styles/Test/Very.yml" with:
+    """
+    extends: existence
+    message: 'Avoid "%s"'
+    tokens:
+      - very
+    """
+    And a file named ".vale.ini" with:
+    """
+    StylesPath = styles
+
+    [*]
+    BasedOnStyles = Test
+    """
+    And a file named "report.tmpl" with:
+    """
+    {{range .}}{{range .Alerts}}{"message": "{{jsonEscape .Message}}", "span": {{toJSON .Span}}}{{end}}{{end}}
+    """
+    When I run vale "--output=report.tmpl 'This is very good.'"
+    Then the output should contain:
+    """
+    {"message": "Avoid \"very\"", "span": [9,12]}
+    """
+
+  Scenario: Use a missing template
+    When I run vale "--output=missing.tmpl 'This is very good.'"
+    Then the output should contain "missing.tmpl: no such file or directory"
+    And the exit status should be 1
+
+  Scenario: Use an invalid template
+    Given a file named "bad.tmpl" with:
+    """
+    {{ range . }}
+
+    """
+    When I run vale "--output=bad.tmpl 'This is very good.'"
+    Then the output should contain "template: bad.tmpl"
+    And the output should not contain "very"
+    And the exit status should be 1
+
+  Scenario: Lint a file with JSON Lines output
+    When I lint "--output=jsonl test.txt"
+    Then the output should contain exactly:
+    """
+    {"Path":"test.txt","Check":"vale.Annotations","Description":"","Line":1,"Link":"","Message":"'NOTE' left in text","Severity":"suggestion","Span":[27,30],"Hide":false,"Match":"","Suggestions":null}
+    {"Path":"test.txt","Check":"vale.Annotations","Description":"","Line":4,"Link":"","Message":"'XXX' left in text","Severity":"suggestion","Span":[12,14],"Hide":false,"Match":"","Suggestions":null}
+    {"Path":"test.txt","Check":"vale.Annotations","Description":"","Line":4,"Link":"","Message":"'TODO' left in text","Severity":"suggestion","Span":[66,69],"Hide":false,"Match":"","Suggestions":null}
+    """
+    And the exit status should be 0
@@ -0,0 +1,13 @@
+Feature: Profile
+  Scenario: Report the slowest rules and files
+    Given a file named "docs/test.md" with:
+    """
+    It is very good.
+
+    """
+    When I run vale "--profile docs"
+    Then the output should contain "docs/test.md:1:7:vale.Editorializing:Consider removing 'very'"
+    And the stderr should contain "Slowest rules:"
+    And the stderr should contain "Slowest files:"
+    And the stderr should contain "vale.Editorializing"
+    And the stderr should contain "markdown"
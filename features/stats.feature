@@ -0,0 +1,49 @@
+Feature: Stats
+  Background:
+    Given a file named "test.md" with:
+    """
+    # Title
+
+    This is very good. This is very bad.
+
+    A paragraph.
+
+    """
+
+  Scenario: Print document statistics
+    When I run vale "stats test.md"
+    Then the output should contain "FILE"
+    And the output should contain "test.md"
+    And the output should contain "DALE-CHALL"
+    And the exit status should be 0
+
+  Scenario: Print document statistics as JSON
+    When I run vale "--output=JSON stats test.md"
+    Then the output should contain:
+    """
+    "Words": 11
+    """
+    And the output should contain:
+    """
+    "Sentences": 3
+    """
+    And the output should contain:
+    """
+    "Headings": 1
+    """
+    And the output should contain:
+    """
+    "FleschReadingEase"
+    """
+    And the exit status should be 0
+
+  Scenario: Include metrics in JSON output
+    When I run vale "--output=JSON --metrics test.md"
+    Then the output should contain:
+    """
+    "Alerts": [
+    """
+    And the output should contain:
+    """
+    "Paragraphs": 2
+    """
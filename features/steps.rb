@@ -63,3 +63,11 @@ When(/^I lint string "(.*)"$/) do |string|
     step %(I run `#{exe} '#{string}'`)
   end
 end
+
+When(/^I run vale with diff "([^\s]+)"$/) do |diff|
+  if OS.windows?
+    step %(I run `PowerShell -Command Get-Content #{diff} | #{exe} --diff-stdin`)
+  else
+    step %(I run `bash -c 'cat #{diff} | #{exe} --diff-stdin'`)
+  end
+end
@@ -0,0 +1,65 @@
+Feature: Test
+  Background:
+    Given a file named "styles/Test/Utilize.yml" with:
+    """
+    extends: substitution
+    message: "Use '%s' instead of '%s'."
+    level: error
+    swap:
+      utilize: use
+    examples:
+      valid:
+        - We use it.
+      invalid:
+        - text: Please utilize it.
+          alerts:
+            - line: 1
+              span: [8, 14]
+              message: Use 'use' instead of 'utilize'.
+    """
+    And a file named "styles/Test/fixtures/Utilize/invalid.md" with:
+    """
+    # Heading
+
+    We utilize it.
+
+    """
+
+  Scenario: Test a style's examples
+    When I run vale "test styles"
+    Then the output should contain exactly:
+    """
+    ok   Test.Utilize (3 examples)
+    """
+    And the exit status should be 0
+
+  Scenario: Report a failing example
+    Given a file named "styles/Test/fixtures/Utilize/valid.md" with:
+    """
+    You can utilize it.
+
+    """
+    When I run vale "test styles"
+    Then the output should contain "FAIL Test.Utilize"
+    And the output should contain "1 of 4 examples failed"
+    And the exit status should be 1
+
+  Scenario: Treat an example that looks like a path as text
+    Given a file named "styles/Test/Utilize.yml" with:
+    """
+    extends: substitution
+    message: "Use '%s' instead of '%s'."
+    swap:
+      utilize: use
+    examples:
+      valid:
+        - README.md
+    """
+    And a file named "README.md" with:
+    """
+    We utilize it.
+
+    """
+    When I run vale "test styles"
+    Then the output should contain "ok   Test.Utilize (2 examples)"
+    And the exit status should be 0
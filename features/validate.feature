@@ -0,0 +1,34 @@
+Feature: Validate
+  Background:
+    Given a file named "styles/Test/Very.yml" with:
+    """
+    extends: existence
+    message: "Avoid '%s'"
+    ignorcase: true
+    tokens:
+      - very
+    """
+    And a file named ".vale.ini" with:
+    """
+    StylesPath = styles
+
+    [*]
+    BasedOnStyles = Test
+    """
+    And a file named "test.md" with:
+    """
+    This is very good.
+
+    """
+
+  Scenario: Validate a StylesPath
+    When I run vale "validate styles"
+    Then the output should contain "styles/Test/Very.yml:3: ignorcase: unknown key for 'existence'"
+    And the output should contain "found 1 problem in 1 rule"
+    And the exit status should be 1
+
+  Scenario: Lint in strict mode
+    When I run vale "--strict test.md"
+    Then the output should contain "Very.yml:3: ignorcase: unknown key for 'existence'"
+    And the output should not contain "Avoid 'very'"
+    And the exit status should be 2
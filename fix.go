@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// fixFiles applies the suggestions made by each file's alerts, either in place
+// or, if `dryRun` is set, by printing a unified diff to stdout.
+func fixFiles(linted []*core.File, dryRun bool) error {
+	for _, f := range linted {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return err
+		}
+		src, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			return err
+		}
+
+		fixed, errs := f.Fix(src)
+		for _, e := range errs {
+			fmt.Fprintln(os.Stderr, e.Error())
+		}
+
+		if string(fixed) == string(src) {
+			continue
+		} else if dryRun {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        splitLines(string(src)),
+				B:        splitLines(string(fixed)),
+				FromFile: "a/" + f.Path,
+				ToFile:   "b/" + f.Path,
+				Context:  3,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Print(diff)
+		} else if err = ioutil.WriteFile(f.Path, fixed, info.Mode()); err != nil {
+			return err
+		} else {
+			fmt.Printf("fixed %s\n", f.Path)
+		}
+	}
+	return nil
+}
+
+// splitLines splits `s` into newline-terminated lines for difflib.
+//
+// NOTE: `difflib.SplitLines` adds an empty line to text that already ends in a
+// newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// A Cache stores the results of linting files on disk, so that unchanged
+// files don't need to be linted again.
+//
+// Each entry is keyed by the file's path and content along with everything
+// else that affects its alerts: the effective config, the contents of
+// StylesPath and Vale's version (which covers the built-in rules).
+type Cache struct {
+	dir string
+	key []byte
+}
+
+// A cacheEntry is the part of a File that linting produces.
+type cacheEntry struct {
+	Alerts  []core.Alert
+	Counts  map[string]int
+	Summary string
+}
+
+// NewCache creates a Cache, stored in `dir`, for linting with `config`.
+func NewCache(dir string, config *core.Config, version string) (*Cache, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	} else if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	io.WriteString(h, version+"\x00")
+
+	// We ignore the settings that only affect how we present alerts.
+	effective := *config
+	effective.Path, effective.CacheDir = "", ""
+	effective.Output, effective.Wrap, effective.NoExit = "", false, false
+	effective.Sorted, effective.Normalize, effective.Relative = false, false, false
+	effective.Metrics, effective.Strict, effective.Profile = false, false, false
+	effective.Workers, effective.Timeout, effective.WorkDir = 0, 0, ""
+	effective.Gates = core.NewGates()
+	b, err := json.Marshal(effective)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(b)
+
+	if config.StylesPath != "" {
+		err = filepath.Walk(config.StylesPath, func(fp string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return err
+			}
+			content, err := ioutil.ReadFile(fp)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(config.StylesPath, fp)
+			io.WriteString(h, "\x00"+filepath.ToSlash(rel)+"\x00")
+			h.Write(content)
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return &Cache{dir: dir, key: h.Sum(nil)}, nil
+}
+
+// Load populates `f` from the cache, returning whether or not it was found.
+func (c *Cache) Load(f *core.File) bool {
+	var entry cacheEntry
+
+	b, err := ioutil.ReadFile(c.path(f))
+	if err != nil || json.Unmarshal(b, &entry) != nil {
+		return false
+	}
+	f.Alerts = entry.Alerts
+	for k, v := range entry.Counts {
+		f.Counts[k] = v
+	}
+	f.Summary.WriteString(entry.Summary)
+	return true
+}
+
+// Store records the results of linting `f`.
+func (c *Cache) Store(f *core.File) error {
+	b, err := json.Marshal(cacheEntry{
+		Alerts: f.Alerts, Counts: f.Counts, Summary: f.Summary.String()})
+	if err != nil {
+		return err
+	}
+
+	// We write to a temporary file first, so that a concurrent Load never
+	// sees a partial entry.
+	path := c.path(f)
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// isDir determines if `dir` is the cache's directory, which we shouldn't
+// lint.
+func (c *Cache) isDir(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	return err == nil && abs == c.dir
+}
+
+// path returns the location of f's entry.
+func (c *Cache) path(f *core.File) string {
+	h := sha256.New()
+	h.Write(c.key)
+	io.WriteString(h, "\x00"+f.Path+"\x00"+f.Content)
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
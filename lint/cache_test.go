@@ -0,0 +1,73 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.md")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("It is very good.\n"), 0644))
+
+	config := core.NewConfig()
+	cache, err := NewCache(filepath.Join(dir, ".vale-cache"), config, "test")
+	assert.Nil(t, err)
+
+	linter := Linter{Config: config, CheckManager: check.NewManager(config), Cache: cache}
+	linted, err := linter.Lint([]string{dir}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(linted))
+	assert.Equal(t, 1, len(linted[0].Alerts))
+
+	// Without any checks, we can only find the alert in our cache.
+	empty := Linter{Config: config, CheckManager: &check.Manager{}, Cache: cache}
+	cached, err := empty.Lint([]string{dir}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, linted[0].Alerts, cached[0].Alerts)
+	assert.Equal(t, linted[0].Counts, cached[0].Counts)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("It is very, very good.\n"), 0644))
+	changed, err := empty.Lint([]string{dir}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(changed[0].Alerts))
+}
+
+func TestCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	rule := filepath.Join(dir, "styles", "Test", "Rule.yml")
+	assert.Nil(t, os.MkdirAll(filepath.Dir(rule), 0755))
+	assert.Nil(t, ioutil.WriteFile(rule, []byte("extends: existence\n"), 0644))
+
+	config := core.NewConfig()
+	config.StylesPath = filepath.Join(dir, "styles")
+	key := func() string {
+		cache, err := NewCache(filepath.Join(dir, "cache"), config, "test")
+		assert.Nil(t, err)
+		return string(cache.key)
+	}
+
+	original := key()
+	config.Output = "JSON"
+	assert.Equal(t, original, key())
+
+	config.MinAlertLevel = 0
+	assert.NotEqual(t, original, key())
+
+	config.MinAlertLevel = 1
+	assert.Equal(t, original, key())
+	assert.Nil(t, ioutil.WriteFile(rule, []byte("extends: substitution\n"), 0644))
+	assert.NotEqual(t, original, key())
+}
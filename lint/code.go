@@ -2,6 +2,7 @@ package lint
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/ValeLint/vale/core"
@@ -10,7 +11,7 @@ import (
 
 // lintCode lints source code -- whether it be a markup codeblock, a complete
 // file, or some other portion of text.
-func (l *Linter) lintCode(f *core.File) int {
+func (l *Linter) lintCode(ctx context.Context, f *core.File) int {
 	var line, match, txt string
 	var lnLength, padding int
 	var block bytes.Buffer
@@ -28,7 +29,7 @@ func (l *Linter) lintCode(f *core.File) int {
 	ignore := false
 	inBlock := false
 
-	for f.Scanner.Scan() {
+	for f.Scanner.Scan() && ctx.Err() == nil {
 		line = core.PrepText(f.Scanner.Text() + "\n")
 		lnLength = len(line)
 		lines++
@@ -40,7 +41,7 @@ func (l *Linter) lintCode(f *core.File) int {
 				txt = block.String()
 				b := NewBlock(
 					txt, txt, "", fmt.Sprintf(scope, "text.comment.block"))
-				l.lintText(f, b, lines+1, 0)
+				l.lintText(ctx, f, b, lines+1, 0)
 				block.Reset()
 				inBlock = false
 			} else {
@@ -53,7 +54,7 @@ func (l *Linter) lintCode(f *core.File) int {
 			padding = lnLength - len(match)
 			b := NewBlock(
 				match, match, "", fmt.Sprintf(scope, "text.comment.line"))
-			l.lintText(f, b, lines, padding-1)
+			l.lintText(ctx, f, b, lines, padding-1)
 		} else if match = blockStart.FindString(line); len(match) > 0 && !ignore {
 			// We've found the start of a block comment.
 			block.WriteString(line)
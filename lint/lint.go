@@ -28,12 +28,14 @@ The package is split into core linting logic (this file), source code
 package lint
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/ValeLint/vale/check"
 	"github.com/ValeLint/vale/core"
@@ -41,9 +43,20 @@ import (
 )
 
 // A Linter lints a File.
+//
+// A Linter (along with its CheckManager) is safe for concurrent use: all of
+// the state that changes while linting (e.g., a File's comments and
+// sequences) belongs to the File being linted.
 type Linter struct {
 	Config       *core.Config   // command-line and config file settings
 	CheckManager *check.Manager // loaded checks
+	Cache        *Cache         // (optional) previous results
+	Profile      *core.Profile  // (optional) records where linting spends its time
+
+	// Skipped, if set, is called (from the goroutine walking the input) for
+	// each file that we'd otherwise lint but skip -- e.g., because it's
+	// larger than Config.MaxFileSize.
+	Skipped func(core.Error)
 }
 
 // A Block represents a section of text.
@@ -65,13 +78,59 @@ func NewBlock(ctx, txt, raw, sel string) Block {
 
 // LintString src according to its format.
 func (l Linter) LintString(src string) ([]*core.File, error) {
-	return []*core.File{l.lintFile(src)}, nil
+	return []*core.File{l.lintFile(context.Background(), src)}, nil
+}
+
+// LintStringAs lints src according to the format of `path`, without reading
+// anything from disk.
+func (l Linter) LintStringAs(src, path string) ([]*core.File, error) {
+	return l.LintStringAsContext(context.Background(), src, path)
+}
+
+// LintStringAsContext is like LintStringAs, but stops linting (and records an
+// error on the returned File) if `ctx` is canceled.
+func (l Linter) LintStringAsContext(ctx context.Context, src, path string) ([]*core.File, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return []*core.File{l.lintFormat(ctx, core.NewFileFromString(src, path, l.Config))}, nil
 }
 
 // Lint src according to its format.
 func (l Linter) Lint(input []string, pat string) ([]*core.File, error) {
 	var linted []*core.File
 
+	err := l.LintEach(input, pat, func(f *core.File) error {
+		linted = append(linted, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Config.Sorted {
+		sort.Sort(core.ByName(linted))
+	}
+
+	return linted, nil
+}
+
+// LintEach lints every file in `input` that matches `pat`, passing each one
+// to `fn` as soon as it's done. Unlike Lint, it doesn't sort the files or
+// hold on to them; if `fn` returns an error, we stop linting.
+func (l Linter) LintEach(input []string, pat string, fn func(*core.File) error) error {
+	return l.LintEachContext(context.Background(), input, pat, fn)
+}
+
+// LintEachContext is like LintEach, but stops linting -- including walking
+// directories and running any markup converters -- and returns ctx's error
+// if `ctx` is canceled.
+func (l Linter) LintEachContext(ctx context.Context, input []string, pat string, fn func(*core.File) error) error {
+	fs, err := l.newFilters(pat)
+	if err != nil {
+		return err
+	}
+
 	done := make(chan core.File)
 	defer close(done)
 
@@ -79,43 +138,40 @@ func (l Linter) Lint(input []string, pat string) ([]*core.File, error) {
 		if !(core.IsDir(src) || core.FileExists(src)) {
 			continue
 		}
-		filesChan, errc := l.lintFiles(done, src, core.NewGlob(pat))
+		filesChan, errc := l.lintFiles(ctx, done, src, fs)
 		for f := range filesChan {
 			if l.Config.Normalize {
 				f.Path = filepath.ToSlash(f.Path)
 			}
-			linted = append(linted, f)
+			if err := fn(f); err != nil {
+				return err
+			}
 		}
 		if err := <-errc; err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	if l.Config.Sorted {
-		sort.Sort(core.ByName(linted))
-	}
-
-	return linted, nil
+	return ctx.Err()
 }
 
 // lintFiles walks the `root` directory, creating a new goroutine to lint any
-// file that matches the given glob pattern.
-func (l Linter) lintFiles(done <-chan core.File, root string, glob core.Glob) (<-chan *core.File, <-chan error) {
+// file that passes the given filters (see `walker`).
+func (l Linter) lintFiles(ctx context.Context, done <-chan core.File, root string, fs filters) (<-chan *core.File, <-chan error) {
 	filesChan := make(chan *core.File)
 	errc := make(chan error, 1)
-	ignore := []string{".", "_"}
 	go func() {
-		wg := sizedwaitgroup.New(5)
-		err := filepath.Walk(root, func(fp string, fi os.FileInfo, err error) error {
-			if err != nil || fi.IsDir() {
-				return nil
-			} else if !glob.Match(fp) || core.HasAnyPrefix(fi.Name(), ignore) {
-				return nil
+		wg := sizedwaitgroup.New(l.workers())
+		w := l.newWalker(fs)
+		w.skipped = l.Skipped
+		err := w.walk(root, func(fp string) error {
+			if err := ctx.Err(); err != nil {
+				return err
 			}
 			wg.Add()
 			go func(fp string) {
 				select {
-				case filesChan <- l.lintFile(fp):
+				case filesChan <- l.lintFile(ctx, fp):
 				case <-done:
 				}
 				wg.Done()
@@ -139,80 +195,155 @@ func (l Linter) lintFiles(done <-chan core.File, root string, glob core.Glob) (<
 	return filesChan, errc
 }
 
+// workers returns the number of files we lint at once, which defaults to
+// the number of CPUs.
+func (l Linter) workers() int {
+	if l.Config.Workers > 0 {
+		return l.Config.Workers
+	}
+	return runtime.NumCPU()
+}
+
 // lintFile creates a new `File` from the path `src` and selects a linter based
 // on its format.
 //
 // TODO: remove dependencies on `asciidoctor` and `rst2html`.
-func (l Linter) lintFile(src string) *core.File {
+func (l Linter) lintFile(ctx context.Context, src string) *core.File {
 	file := core.NewFile(src, l.Config)
+	if l.Cache == nil || !core.FileExists(src) {
+		return l.lintFormat(ctx, file)
+	} else if l.Cache.Load(file) {
+		return file
+	}
+	l.lintFormat(ctx, file)
+	if len(file.Errors) == 0 {
+		// We don't cache incomplete results (e.g., from a timeout).
+		if err := l.Cache.Store(file); err != nil {
+			file.AddError(core.ErrorCache, err)
+		}
+	}
+	return file
+}
+
+// lintFormat lints `file` according to its format.
+//
+// We stop linting if `ctx` is canceled or, if our config has a Timeout, once
+// it's expired -- recording the reason in file's errors.
+func (l Linter) lintFormat(ctx context.Context, file *core.File) *core.File {
+	if l.Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Config.Timeout)
+		defer cancel()
+	}
+
+	file.Profile = l.Profile
+	defer l.Profile.AddFile(file.Path, time.Now())
+
 	if file.Format == "markup" && !l.Config.Simple {
 		switch file.NormedExt {
 		case ".adoc":
 			cmd := core.Which([]string{"asciidoctor"})
 			if cmd != "" {
-				l.lintADoc(file, cmd)
+				l.lintADoc(ctx, file, cmd)
 			} else {
-				fmt.Println("asciidoctor not found!")
+				file.AddError(core.ErrorConverter, errors.New("asciidoctor not found"))
 			}
 		case ".md":
-			l.lintMarkdown(file)
+			l.lintMarkdown(ctx, file)
 		case ".rst":
 			cmd := core.Which([]string{"rst2html", "rst2html.py"})
 			runtime := core.Which([]string{"python", "py", "python.exe"})
-			if cmd != "" && runtime != "" {
-				l.lintRST(file, runtime, cmd)
+			if cmd == "" {
+				file.AddError(core.ErrorConverter, errors.New("rst2html not found"))
+			} else if runtime == "" {
+				file.AddError(core.ErrorConverter, errors.New("python (needed by rst2html) not found"))
 			} else {
-				fmt.Println(fmt.Sprintf("can't run rst2html: (%s, %s)!", runtime, cmd))
+				l.lintRST(ctx, file, runtime, cmd)
 			}
 		case ".html":
-			l.lintHTML(file)
+			l.lintHTML(ctx, file)
 		}
 	} else if file.Format == "code" && !l.Config.Simple {
-		l.lintCode(file)
+		l.lintCode(ctx, file)
 	} else {
-		l.lintLines(file)
+		l.lintLines(ctx, file)
+	}
+
+	if err := ctx.Err(); err == context.DeadlineExceeded {
+		file.AddError(core.ErrorTimeout, fmt.Errorf("timed out after %s", l.Config.Timeout))
+	} else if err != nil {
+		file.AddError(core.ErrorCanceled, err)
 	}
 	return file
 }
 
-func (l Linter) lintProse(f *core.File, ctx, txt, raw string, lnTotal, lnLength int) {
+func (l Linter) lintProse(ctx context.Context, f *core.File, parent, txt, raw string, lnTotal, lnLength int) {
 	var b Block
 	text := core.PrepText(txt)
 	rawText := core.PrepText(raw)
 	senScope := "sentence" + f.RealExt
 	parScope := "paragraph" + f.RealExt
 	txtScope := "text" + f.RealExt
-	hasCtx := ctx != ""
+	hasCtx := parent != ""
 	for _, p := range strings.SplitAfter(text, "\n\n") {
-		for _, s := range core.SentenceTokenizer.Tokenize(p) {
+		if ctx.Err() != nil {
+			return
+		}
+		sentences := core.SentenceTokenizer.Tokenize(p)
+		countProse(f, p, sentences)
+		for _, s := range sentences {
 			sent := strings.TrimSpace(s)
 			if hasCtx {
-				b = NewBlock(ctx, sent, "", senScope)
+				b = NewBlock(parent, sent, "", senScope)
 			} else {
 				b = NewBlock(p, sent, "", senScope)
 			}
-			l.lintText(f, b, lnTotal, lnLength)
+			l.lintText(ctx, f, b, lnTotal, lnLength)
 		}
-		l.lintText(f, NewBlock(ctx, p, "", parScope), lnTotal, lnLength)
+		l.lintText(ctx, f, NewBlock(parent, p, "", parScope), lnTotal, lnLength)
 	}
-	l.lintText(f, NewBlock(ctx, text, rawText, txtScope), lnTotal, lnLength)
+	l.lintText(ctx, f, NewBlock(parent, text, rawText, txtScope), lnTotal, lnLength)
 }
 
-func (l Linter) lintLines(f *core.File) {
+func (l Linter) lintLines(ctx context.Context, f *core.File) {
 	var line string
 	lines := 1
 	for f.Scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
 		line = core.PrepText(f.Scanner.Text() + "\n")
-		l.lintText(f, NewBlock("", line, "", "text"+f.RealExt), lines+1, 0)
+		l.lintText(ctx, f, NewBlock("", line, "", "text"+f.RealExt), lines+1, 0)
 		lines++
 	}
+
+	if f.Format != "code" {
+		for _, p := range strings.SplitAfter(f.Content, "\n\n") {
+			countProse(f, p, core.SentenceTokenizer.Tokenize(p))
+		}
+	}
 }
 
-func (l Linter) lintText(f *core.File, blk Block, lines int, pad int) {
+// countProse adds the paragraph `p`, which consists of `sentences`, to f's
+// counts.
+func countProse(f *core.File, p string, sentences []string) {
+	if strings.TrimSpace(p) == "" {
+		return
+	}
+	f.Counts["paragraphs"]++
+	for _, s := range sentences {
+		if strings.TrimSpace(s) != "" {
+			f.Counts["sentences"]++
+		}
+	}
+	f.Counts["words"] += len(strings.Fields(p))
+}
+
+func (l Linter) lintText(ctx context.Context, f *core.File, blk Block, lines int, pad int) {
 	var style, txt string
 	var run bool
 
-	ctx := blk.Context
+	parent := blk.Context
 	min := l.Config.MinAlertLevel
 	hasCode := core.StringInSlice(f.NormedExt, []string{".md", ".adoc", ".rst"})
 	f.ChkToCtx = make(map[string]string)
@@ -255,8 +386,46 @@ func (l Linter) lintText(f *core.File, blk Block, lines int, pad int) {
 			continue
 		}
 
-		for _, a := range chk.Rule(txt, f) {
-			f.AddAlert(a, ctx, txt, lines, pad)
+		start := time.Now()
+		alerts, ok := runRule(ctx, chk, txt, f)
+		l.Profile.AddRule(f.Path, name, start)
+		if !ok {
+			return
+		}
+
+		for _, a := range alerts {
+			f.AddAlert(a, parent, txt, lines, pad)
 		}
 	}
 }
+
+// runRule runs `chk` on `txt`, giving up (and returning false) as soon as
+// `ctx` is done.
+//
+// We can't stop a rule that's already running, so an abandoned rule finishes
+// in the background -- on a copy of `f` (including the slices it can append
+// to), so that it can't touch the File we've returned.
+func runRule(ctx context.Context, chk check.Check, txt string, f *core.File) ([]core.Alert, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	} else if ctx.Done() == nil {
+		// ctx can't be canceled, so there's no need for a goroutine.
+		return chk.Rule(txt, f), true
+	}
+
+	cp := *f
+	cp.Alerts = append([]core.Alert(nil), f.Alerts...)
+	cp.Sequences = append([]string(nil), f.Sequences...)
+	done := make(chan []core.Alert, 1)
+	go func() {
+		done <- chk.Rule(txt, &cp)
+	}()
+
+	select {
+	case alerts := <-done:
+		f.Sequences = cp.Sequences
+		return alerts, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
@@ -1,9 +1,19 @@
 package lint
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ValeLint/vale/check"
 	"github.com/ValeLint/vale/core"
@@ -46,12 +56,136 @@ func TestGenderBias(t *testing.T) {
 	}
 }
 
+func TestCounts(t *testing.T) {
+	config := core.NewConfig()
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	src := "# Title\n\nThis is the first sentence. This is the second.\n\nA paragraph.\n"
+	for ext, expected := range map[string]map[string]int{
+		".md":  {"headings": 1, "paragraphs": 2, "sentences": 3, "words": 12},
+		".txt": {"paragraphs": 3, "sentences": 4, "words": 13},
+	} {
+		linted, err := linter.LintStringAs(src, "test"+ext)
+		assert.Nil(t, err)
+		for k, v := range expected {
+			assert.Equal(t, v, linted[0].Counts[k], ext+" "+k)
+		}
+	}
+}
+
+func TestLintEach(t *testing.T) {
+	config := core.NewConfig()
+	config.Sorted = true
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	linted, err := linter.Lint([]string{"../fixtures/comments"}, "*")
+	assert.Nil(t, err)
+	assert.True(t, len(linted) > 1)
+
+	seen := 0
+	err = linter.LintEach([]string{"../fixtures/comments"}, "*", func(f *core.File) error {
+		seen++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, len(linted), seen)
+
+	stop := errors.New("stop")
+	seen = 0
+	err = linter.LintEach([]string{"../fixtures/comments"}, "*", func(f *core.File) error {
+		seen++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, seen)
+}
+
+// summarize describes each of f's alerts, in a stable order -- alerts at the
+// same position may be found in any order.
+func summarize(f *core.File) []string {
+	alerts := []string{}
+	for _, a := range f.Alerts {
+		alerts = append(alerts, fmt.Sprintf("%d:%v:%s", a.Line, a.Span, a.Check))
+	}
+	sort.Strings(alerts)
+	return alerts
+}
+
+func TestConcurrentLintString(t *testing.T) {
+	config := core.NewConfig()
+	config.StylesPath, _ = filepath.Abs("../styles")
+	config.GBaseStyles = []string{"vale", "18F", "jQuery", "proselint", "write-good"}
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	docs := map[string]string{
+		"a.md":   "# Very good\n\nIt is *very* good, basically. This is `very` good.\n\n<!-- vale off -->\nvery\n<!-- vale on -->\n",
+		"b.html": "<p>It is <em>very</em> good.</p><pre>very</pre>",
+		"c.py":   "# It is very good.\nx = 1  # very\n",
+		"d.txt":  "It is very good.\nIt is very, very good.\n",
+	}
+	expected := map[string][]string{}
+	for name, src := range docs {
+		linted, err := linter.LintStringAs(src, name)
+		assert.Nil(t, err)
+		expected[name] = summarize(linted[0])
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		for name, src := range docs {
+			wg.Add(1)
+			go func(name, src string) {
+				defer wg.Done()
+				linted, err := linter.LintStringAs(src, name)
+				assert.Nil(t, err)
+				assert.Equal(t, expected[name], summarize(linted[0]), name)
+			}(name, src)
+		}
+	}
+	wg.Wait()
+}
+
+func TestWorkers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "workers")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for i := 0; i < 100; i++ {
+		src := strings.Repeat("It is very good. ", i%7+1)
+		ext := []string{".md", ".txt", ".html"}[i%3]
+		err = ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%03d%s", i, ext)), []byte(src), 0644)
+		assert.Nil(t, err)
+	}
+
+	lintWith := func(workers int) map[string][]string {
+		config := core.NewConfig()
+		config.Workers = workers
+		linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+		linted, err := linter.Lint([]string{dir}, "*")
+		assert.Nil(t, err)
+
+		results := map[string][]string{}
+		for _, f := range linted {
+			results[f.Path] = summarize(f)
+		}
+		return results
+	}
+
+	expected := lintWith(1)
+	assert.Len(t, expected, 100)
+	assert.Equal(t, expected, lintWith(16))
+}
+
 func benchmarkLint(path string, b *testing.B) {
 	path, err := filepath.Abs(path)
 	if err != nil {
 		panic(err)
 	}
-	config := core.LoadConfig()
+	config, err := core.LoadConfig("")
+	if err != nil {
+		panic(err)
+	}
 	mgr := check.NewManager(config)
 	linter := Linter{Config: config, CheckManager: mgr}
 	for n := 0; n < b.N; n++ {
@@ -66,3 +200,137 @@ func BenchmarkLintRST(b *testing.B) {
 func BenchmarkLintMD(b *testing.B) {
 	benchmarkLint("../fixtures/benchmarks/bench.md", b)
 }
+
+func TestTimeout(t *testing.T) {
+	config := core.NewConfig()
+	config.Timeout = time.Nanosecond
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	linted, err := linter.LintStringAs("It is very good.\n", "test.md")
+	assert.Nil(t, err)
+	assert.Len(t, linted[0].Errors, 1)
+	assert.Equal(t, core.ErrorTimeout, linted[0].Errors[0].Kind)
+	assert.Contains(t, linted[0].Errors[0].Error(), "test.md: timed out after 1ns")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = linter.LintEachContext(ctx, []string{"../fixtures/comments"}, "*", func(f *core.File) error {
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestTimeoutKillsConverter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of asciidoctor")
+	}
+
+	dir, err := ioutil.TempDir("", "timeout")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// An asciidoctor that never finishes.
+	script := filepath.Join(dir, "asciidoctor")
+	assert.Nil(t, ioutil.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0755))
+
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+
+	config := core.NewConfig()
+	config.Timeout = 100 * time.Millisecond
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	start := time.Now()
+	linted, err := linter.LintStringAs("It is very good.\n", "test.adoc")
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 10*time.Second)
+	assert.Len(t, linted[0].Errors, 1)
+	assert.Empty(t, linted[0].Alerts)
+}
+
+func TestMissingConverter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "converter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A PATH without asciidoctor or rst2html.
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", dir)
+
+	config := core.NewConfig()
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	for ext, msg := range map[string]string{
+		".adoc": "test.adoc: asciidoctor not found",
+		".rst":  "test.rst: rst2html not found",
+	} {
+		linted, err := linter.LintStringAs("It is very good.\n", "test"+ext)
+		assert.Nil(t, err)
+		assert.Len(t, linted[0].Errors, 1)
+		assert.Equal(t, core.ErrorConverter, linted[0].Errors[0].Kind)
+		assert.Equal(t, msg, linted[0].Errors[0].Error())
+		assert.Empty(t, linted[0].Alerts)
+	}
+}
+
+func TestTimeoutAbandonsRule(t *testing.T) {
+	config := core.NewConfig()
+	config.Timeout = 100 * time.Millisecond
+
+	// A rule that never finishes in time.
+	mgr := &check.Manager{Config: config, AllChecks: map[string]check.Check{
+		"vale.Slow": {
+			Level: 1,
+			Scope: core.Selector{Value: "text"},
+			Rule: func(txt string, f *core.File) []core.Alert {
+				time.Sleep(30 * time.Second)
+				return nil
+			},
+		},
+	}}
+	linter := Linter{Config: config, CheckManager: mgr}
+
+	start := time.Now()
+	linted, err := linter.LintStringAs("It is very good.\n", "test.txt")
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 10*time.Second)
+	assert.Len(t, linted[0].Errors, 1)
+	assert.Equal(t, core.ErrorTimeout, linted[0].Errors[0].Kind)
+}
+
+func TestAbandonedRuleKeepsOwnFile(t *testing.T) {
+	f := core.NewFileFromString("It is very good.\n", "test.txt", core.NewConfig())
+	// Spare capacity, so that a shallow copy would share its backing arrays.
+	f.Alerts = make([]core.Alert, 0, 64)
+	f.Sequences = make([]string, 0, 64)
+
+	finished := make(chan bool)
+	chk := check.Check{Rule: func(txt string, f *core.File) []core.Alert {
+		// A rule that keeps writing to the File after it's timed out.
+		defer close(finished)
+		for i := 0; i < 50; i++ {
+			f.Sequences = append(f.Sequences[:0], "abandoned")
+			f.Alerts = append(f.Alerts[:0], core.Alert{Check: "vale.Slow"})
+			time.Sleep(2 * time.Millisecond)
+		}
+		return nil
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok := runRule(ctx, chk, f.Content, f)
+	assert.False(t, ok)
+
+	// The caller carries on with the File while the rule is still running.
+	for i := 0; i < 50; i++ {
+		f.Sequences = append(f.Sequences[:0], "live")
+		f.Alerts = append(f.Alerts[:0], core.Alert{Check: "vale.Live"})
+		time.Sleep(time.Millisecond)
+	}
+	<-finished
+
+	assert.Equal(t, []string{"live"}, f.Sequences)
+	assert.Equal(t, "vale.Live", f.Alerts[0].Check)
+}
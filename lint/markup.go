@@ -2,9 +2,12 @@ package lint
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/ValeLint/vale/core"
@@ -45,7 +48,6 @@ var commonExtensions = 0 |
 	blackfriday.EXTENSION_NO_INTRA_EMPHASIS |
 	blackfriday.EXTENSION_TABLES |
 	blackfriday.EXTENSION_FENCED_CODE
-var renderer = blackfriday.HtmlRenderer(commonHTMLFlags, "", "")
 var options = blackfriday.Options{Extensions: commonExtensions}
 var reFrontMatter = regexp.MustCompile(`^(?s)---\n([^{]+?)\n---`)
 
@@ -69,7 +71,7 @@ var tagToScope = map[string]string{
 	"li": "text.list",
 }
 
-func (l Linter) lintHTMLTokens(f *core.File, ctx string, fsrc []byte, offset int) {
+func (l Linter) lintHTMLTokens(ctx context.Context, f *core.File, parent string, fsrc []byte, offset int) {
 	var txt, attr, raw string
 	var tokt html.TokenType
 	var tok html.Token
@@ -101,7 +103,7 @@ func (l Linter) lintHTMLTokens(f *core.File, ctx string, fsrc []byte, offset int
 		txt = html.UnescapeString(strings.TrimSpace(tok.Data))
 
 		skipClass = core.StringInSlice(attr, skipClasses)
-		if tokt == html.ErrorToken {
+		if tokt == html.ErrorToken || ctx.Err() != nil {
 			break
 		} else if tokt == html.StartTagToken && core.StringInSlice(txt, skipTags) {
 			inBlock = true
@@ -127,10 +129,10 @@ func (l Linter) lintHTMLTokens(f *core.File, ctx string, fsrc []byte, offset int
 			content := buf.String()
 			actual := act.String()
 			if content != "" {
-				l.lintScope(f, ctx, content, actual, tagHistory, lines)
+				l.lintScope(ctx, f, parent, content, actual, tagHistory, lines)
 			}
 			for _, s := range queue {
-				ctx = updateCtx(ctx, s, html.TextToken)
+				parent = updateCtx(parent, s, html.TextToken)
 			}
 			queue = []string{}
 			tagHistory = []string{}
@@ -139,14 +141,14 @@ func (l Linter) lintHTMLTokens(f *core.File, ctx string, fsrc []byte, offset int
 		}
 
 		attr = getAttribute(tok, "class")
-		ctx = clearElements(ctx, tok)
+		parent = clearElements(parent, tok)
 	}
 
 	summary := NewBlock("", f.Summary.String(), "", "summary."+f.RealExt)
-	l.lintText(f, summary, lines, 0)
+	l.lintText(ctx, f, summary, lines, 0)
 }
 
-func (l Linter) lintScope(f *core.File, ctx, txt, raw string, tags []string, lines int) {
+func (l Linter) lintScope(ctx context.Context, f *core.File, parent, txt, raw string, tags []string, lines int) {
 	for _, tag := range tags {
 		scope, match := tagToScope[tag]
 		if match || heading.MatchString(tag) {
@@ -154,9 +156,11 @@ func (l Linter) lintScope(f *core.File, ctx, txt, raw string, tags []string, lin
 				scope = scope + f.RealExt
 			} else {
 				scope = "text.heading." + tag + f.RealExt
+				f.Counts["headings"]++
 			}
 			txt = strings.TrimLeft(txt, " ")
-			l.lintText(f, NewBlock(ctx, txt, raw, scope), lines, 0)
+			f.Counts["words"] += len(strings.Fields(txt))
+			l.lintText(ctx, f, NewBlock(parent, txt, raw, scope), lines, 0)
 			return
 		}
 	}
@@ -164,7 +168,7 @@ func (l Linter) lintScope(f *core.File, ctx, txt, raw string, tags []string, lin
 	// NOTE: We don't include headings, list items, or table cells (which are
 	// processed above) in our Summary content.
 	f.Summary.WriteString(raw + " ")
-	l.lintProse(f, ctx, txt, raw, lines, 0)
+	l.lintProse(ctx, f, parent, txt, raw, lines, 0)
 }
 
 func codify(ext, text string) string {
@@ -229,42 +233,59 @@ func updateCtx(ctx string, txt string, tokt html.TokenType) string {
 	return ctx
 }
 
-func (l Linter) lintHTML(f *core.File) {
-	l.lintHTMLTokens(f, f.Content, []byte(f.Content), 0)
+func (l Linter) lintHTML(ctx context.Context, f *core.File) {
+	l.lintHTMLTokens(ctx, f, f.Content, []byte(f.Content), 0)
 }
 
-func (l Linter) lintMarkdown(f *core.File) {
+func (l Linter) lintMarkdown(ctx context.Context, f *core.File) {
 	s := reFrontMatter.ReplaceAllString(f.Content, "```\n$1\n```")
+	// Our config's patterns have already been validated (see
+	// core.readConfig).
 	for syntax, regexes := range l.Config.IgnorePatterns {
 		sec, err := glob.Compile(syntax)
-		if core.CheckError(err) && sec.Match(".md") {
+		if err == nil && sec.Match(".md") {
 			for _, r := range regexes {
-				pat, err := regexp.Compile(r)
-				if err == nil {
+				if pat, err := regexp.Compile(r); err == nil {
 					s = pat.ReplaceAllString(s, "\n```\n$1\n```\n")
 				}
 			}
 		}
 	}
+	// A renderer holds state (e.g., the header IDs it's seen), so we can't
+	// share one between files that are being linted concurrently.
+	renderer := blackfriday.HtmlRenderer(commonHTMLFlags, "", "")
+
+	start := time.Now()
 	html := blackfriday.MarkdownOptions([]byte(s), renderer, options)
-	l.lintHTMLTokens(f, f.Content, html, 0)
+	l.Profile.AddPhase(f.Path, core.PhaseMarkdown, start)
+	l.lintHTMLTokens(ctx, f, f.Content, html, 0)
 }
 
-func (l Linter) lintRST(f *core.File, python string, rst2html string) {
-	var out bytes.Buffer
+func (l Linter) lintRST(ctx context.Context, f *core.File, python string, rst2html string) {
+	var out, stderr bytes.Buffer
 	var cmd *exec.Cmd
 
 	if runtime.GOOS == "windows" {
 		// rst2html is executable by default on Windows.
-		cmd = exec.Command(python, append([]string{rst2html}, rstArgs...)...)
+		cmd = exec.CommandContext(ctx, python, append([]string{rst2html}, rstArgs...)...)
 	} else {
-		cmd = exec.Command(rst2html, rstArgs...)
+		cmd = exec.CommandContext(ctx, rst2html, rstArgs...)
 	}
 
 	cmd.Stdin = strings.NewReader(reCodeBlock.ReplaceAllString(f.Content, "::"))
 	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	l.Profile.AddPhase(f.Path, core.PhaseRST, start)
 
-	if core.CheckError(cmd.Run()) {
+	if ctx.Err() != nil {
+		// We've killed rst2html; lintFormat records why.
+		return
+	} else if err != nil {
+		f.AddError(core.ErrorConverter, converterError("rst2html", err, stderr))
+	} else {
 		html := bytes.Replace(out.Bytes(), []byte("\r"), []byte(""), -1)
 		bodyStart := bytes.Index(html, []byte("<body>\n"))
 		if bodyStart < 0 {
@@ -277,16 +298,39 @@ func (l Linter) lintRST(f *core.File, python string, rst2html string) {
 				bodyEnd = 0
 			}
 		}
-		l.lintHTMLTokens(f, f.Content, html[bodyStart+7:bodyEnd], 0)
+		l.lintHTMLTokens(ctx, f, f.Content, html[bodyStart+7:bodyEnd], 0)
 	}
 }
 
-func (l Linter) lintADoc(f *core.File, asciidoctor string) {
-	var out bytes.Buffer
-	cmd := exec.Command(asciidoctor, adocArgs...)
+func (l Linter) lintADoc(ctx context.Context, f *core.File, asciidoctor string) {
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, asciidoctor, adocArgs...)
 	cmd.Stdin = strings.NewReader(f.Content)
 	cmd.Stdout = &out
-	if core.CheckError(cmd.Run()) {
-		l.lintHTMLTokens(f, f.Content, out.Bytes(), 0)
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	l.Profile.AddPhase(f.Path, core.PhaseAsciiDoc, start)
+
+	if ctx.Err() != nil {
+		return
+	} else if err != nil {
+		f.AddError(core.ErrorConverter, converterError("asciidoctor", err, stderr))
+	} else {
+		l.lintHTMLTokens(ctx, f, f.Content, out.Bytes(), 0)
+	}
+}
+
+// converterError describes the failure of the markup converter `name`,
+// including the first line it wrote to stderr (if any).
+func converterError(name string, err error, stderr bytes.Buffer) error {
+	msg := strings.TrimSpace(stderr.String())
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	if msg == "" {
+		return fmt.Errorf("%s: %s", name, err)
 	}
+	return fmt.Errorf("%s: %s: %s", name, err, msg)
 }
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// A Snapshot maps a file's path to its last modification.
+type Snapshot map[string]time.Time
+
+// Snapshot records every file in `input` that Lint would lint.
+func (l Linter) Snapshot(input []string, pat string) (Snapshot, error) {
+	files := Snapshot{}
+	found, err := l.Files(input, pat)
+	if err != nil {
+		return nil, err
+	}
+	for _, fp := range found {
+		if fi, err := os.Stat(fp); err == nil {
+			files[fp] = fi.ModTime()
+		}
+	}
+	return files, nil
+}
+
+// StylesSnapshot records `config`'s file and every rule on its StylesPath.
+func StylesSnapshot(config *core.Config) Snapshot {
+	files := Snapshot{}
+	if fi, err := os.Stat(config.Path); err == nil && !fi.IsDir() {
+		files[config.Path] = fi.ModTime()
+	}
+	if config.StylesPath != "" {
+		// We skip anything we can't read, rather than giving up on the rest.
+		filepath.Walk(config.StylesPath, func(fp string, fi os.FileInfo, err error) error {
+			if err == nil && !fi.IsDir() && strings.HasSuffix(fp, ".yml") {
+				files[fp] = fi.ModTime()
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// Changed returns the files in s that are new or have been modified since
+// `previous`.
+func (s Snapshot) Changed(previous Snapshot) []string {
+	changed := []string{}
+	for fp, mod := range s {
+		if old, ok := previous[fp]; !ok || !old.Equal(mod) {
+			changed = append(changed, fp)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Equal determines if s and `other` record the same files and modifications.
+func (s Snapshot) Equal(other Snapshot) bool {
+	return len(s) == len(other) && len(s.Changed(other)) == 0
+}
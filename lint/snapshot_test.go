@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotChanged(t *testing.T) {
+	then := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := then.Add(time.Second)
+
+	for _, tt := range []struct {
+		name     string
+		previous Snapshot
+		current  Snapshot
+		changed  []string
+	}{
+		{"unchanged", Snapshot{"a.md": then}, Snapshot{"a.md": then}, []string{}},
+		{"added", Snapshot{"a.md": then}, Snapshot{"a.md": then, "b.md": now}, []string{"b.md"}},
+		{"modified", Snapshot{"a.md": then, "b.md": then}, Snapshot{"a.md": then, "b.md": now}, []string{"b.md"}},
+		{"deleted", Snapshot{"a.md": then, "b.md": then}, Snapshot{"a.md": then}, []string{}},
+		{"all", Snapshot{"a.md": then, "b.md": then}, Snapshot{"b.md": now, "c.md": now}, []string{"b.md", "c.md"}},
+	} {
+		assert.Equal(t, tt.changed, tt.current.Changed(tt.previous), tt.name)
+		assert.Equal(t, tt.name == "unchanged", tt.current.Equal(tt.previous), tt.name)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write("docs/a.md", "Text.")
+	write("docs/b.txt", "Text.")
+
+	config := core.NewConfig()
+	linter := Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	files, err := linter.Snapshot([]string{filepath.Join(dir, "docs")}, "*.md")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "docs", "a.md")}, files.Changed(Snapshot{}))
+
+	// We can't take a snapshot if we can't tell which files we'd lint.
+	config.Exclude = []string{"[ab"}
+	_, err = linter.Snapshot([]string{filepath.Join(dir, "docs")}, "*.md")
+	assert.EqualError(t, err, "invalid glob '[ab': unexpected end of input")
+}
+
+func TestStylesSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "styles")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path, content string, mod time.Time) {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+		assert.Nil(t, os.Chtimes(path, mod, mod))
+	}
+	then := time.Now().Add(-time.Hour)
+	write(".vale.ini", "StylesPath = styles\n", then)
+	write("styles/Test/Rule.yml", "extends: existence\n", then)
+	write("styles/Test/README.md", "Not a rule.", then)
+
+	config := core.NewConfig()
+	config.Path = filepath.Join(dir, ".vale.ini")
+	config.StylesPath = filepath.Join(dir, "styles")
+
+	before := StylesSnapshot(config)
+	assert.Len(t, before, 2)
+
+	// Editing the config file, or adding a rule, calls for a reload.
+	write(".vale.ini", "StylesPath = styles\nMinAlertLevel = error\n", then.Add(time.Minute))
+	after := StylesSnapshot(config)
+	assert.Equal(t, []string{config.Path}, after.Changed(before))
+
+	write("styles/Test/Other.yml", "extends: existence\n", then)
+	assert.False(t, StylesSnapshot(config).Equal(after))
+}
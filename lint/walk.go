@@ -0,0 +1,209 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// sniffLen is the number of bytes we read to decide if a file is binary,
+// matching Git's heuristic.
+const sniffLen = 8000
+
+// A walker finds the files under a root that we should lint.
+type walker struct {
+	linter  Linter
+	filters filters
+	ignorer *core.Ignorer
+	parents map[string]bool  // the real paths of the directories we're in
+	skipped func(core.Error) // (optional) see Linter.Skipped
+}
+
+// filters holds the globs that a file's path must match (`glob` and any of
+// `include`) or mustn't match (any of `exclude`) for us to lint it.
+type filters struct {
+	glob    core.Glob
+	include []core.Glob
+	exclude []core.Glob
+}
+
+// Files returns every file in `input` that Lint would lint.
+func (l Linter) Files(input []string, pat string) ([]string, error) {
+	fs, err := l.newFilters(pat)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, root := range input {
+		err := l.newWalker(fs).walk(root, func(fp string) error {
+			files = append(files, fp)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// newFilters compiles `pat` along with our config's Include and Exclude
+// globs.
+func (l Linter) newFilters(pat string) (filters, error) {
+	glob, err := core.CompileGlob(pat)
+	if err != nil {
+		return filters{}, err
+	}
+	fs := filters{glob: glob}
+	for _, pat := range l.Config.Include {
+		g, err := core.CompileGlob(pat)
+		if err != nil {
+			return filters{}, err
+		}
+		fs.include = append(fs.include, g)
+	}
+	for _, pat := range l.Config.Exclude {
+		g, err := core.CompileGlob(pat)
+		if err != nil {
+			return filters{}, err
+		}
+		fs.exclude = append(fs.exclude, g)
+	}
+	return fs, nil
+}
+
+func (l Linter) newWalker(fs filters) *walker {
+	return &walker{
+		linter: l, filters: fs, ignorer: core.NewIgnorer(l.Config.IgnoreFiles),
+		parents: make(map[string]bool)}
+}
+
+// walk calls `fn` for each file under `root` that we should lint.
+//
+// Unlike filepath.Walk, we follow symbolic links (skipping broken ones and
+// those that lead back to a directory we're already in). The ignore files
+// of `root`'s parents -- up to our config file's directory or, if our config
+// has one, its WorkDir -- apply too, but `root` itself is never ignored.
+func (w *walker) walk(root string, fn func(string) error) error {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil
+	}
+	if w.linter.Config.Path != "" {
+		err = w.ignorer.LoadParents(root, filepath.Dir(w.linter.Config.Path))
+	}
+	if err == nil && w.linter.Config.WorkDir != "" {
+		err = w.ignorer.LoadParents(root, w.linter.Config.WorkDir)
+	}
+	if err != nil {
+		return err
+	}
+	return w.visit(root, fi, true, fn)
+}
+
+func (w *walker) visit(fp string, fi os.FileInfo, isRoot bool, fn func(string) error) error {
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Stat(fp)
+		if err != nil {
+			// A broken link.
+			return nil
+		}
+		fi = target
+	}
+
+	if !fi.IsDir() {
+		if w.skipFile(fp, fi, isRoot) {
+			return nil
+		}
+		return fn(fp)
+	} else if !isRoot && w.skipDir(fp, fi) {
+		return nil
+	}
+
+	real, err := filepath.EvalSymlinks(fp)
+	if err == nil {
+		real, err = filepath.Abs(real)
+	}
+	if err != nil || w.parents[real] {
+		return nil
+	}
+	w.parents[real] = true
+	defer delete(w.parents, real)
+
+	if err = w.ignorer.LoadDir(fp); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(fp)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if err = w.visit(filepath.Join(fp, entry.Name()), entry, false, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipDir determines if we should skip the directory `fp`.
+func (w *walker) skipDir(fp string, fi os.FileInfo) bool {
+	if fi.Name() == ".git" {
+		return true
+	} else if w.linter.Cache != nil && w.linter.Cache.isDir(fp) {
+		return true
+	}
+	return w.ignorer.Match(fp, true)
+}
+
+// skipFile determines if we should skip the file `fp`: it doesn't match our
+// globs, it's ignored (and not a root), or it's binary or too large.
+func (w *walker) skipFile(fp string, fi os.FileInfo, isRoot bool) bool {
+	if !w.filters.glob.Match(fp) || core.HasAnyPrefix(fi.Name(), []string{".", "_"}) {
+		return true
+	}
+	for _, g := range w.filters.exclude {
+		if g.Match(fp) {
+			return true
+		}
+	}
+	if len(w.filters.include) > 0 {
+		included := false
+		for _, g := range w.filters.include {
+			included = included || g.Match(fp)
+		}
+		if !included {
+			return true
+		}
+	}
+
+	if !isRoot && w.ignorer.Match(fp, false) {
+		return true
+	} else if max := w.linter.Config.MaxFileSize; max > 0 && fi.Size() > max {
+		if w.skipped != nil {
+			w.skipped(core.NewError(core.ErrorSkipped, fp, fmt.Errorf("larger than %d bytes", max)))
+		}
+		return true
+	}
+	return isBinary(fp)
+}
+
+// isBinary determines if the file `fp` looks binary -- i.e., if it contains a
+// NUL byte near its start.
+func isBinary(fp string) bool {
+	f, err := os.Open(fp)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
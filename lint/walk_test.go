@@ -0,0 +1,85 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "walk")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write("docs/a.md", "Text.")
+	write("docs/b.txt", "Text.")
+	write("docs/node_modules/c.md", "Text.")
+	write("docs/image.png", "\x89PNG\x00\x00")
+	write("docs/large.md", "Some text that's larger than our limit.")
+	write("docs/.hidden.md", "Text.")
+	write("other/d.md", "Text.")
+	write("docs/.valeignore", "node_modules/\n")
+	assert.Nil(t, os.Symlink(filepath.Join(dir, "other"), filepath.Join(dir, "docs", "other")))
+	assert.Nil(t, os.Symlink(filepath.Join(dir, "docs"), filepath.Join(dir, "docs", "loop")))
+	assert.Nil(t, os.Symlink("missing.md", filepath.Join(dir, "docs", "broken.md")))
+
+	config := core.NewConfig()
+	config.MaxFileSize = 20
+	skipped := []core.Error{}
+	linter := Linter{Config: config, CheckManager: check.NewManager(config), Skipped: func(e core.Error) {
+		skipped = append(skipped, e)
+	}}
+
+	rel := func(files []string) []string {
+		for i, f := range files {
+			files[i], _ = filepath.Rel(dir, f)
+			files[i] = filepath.ToSlash(files[i])
+		}
+		return files
+	}
+
+	files, err := linter.Files([]string{filepath.Join(dir, "docs")}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"docs/a.md", "docs/b.txt", "docs/other/d.md"}, rel(files))
+
+	// Only linting reports the files it skips.
+	assert.Empty(t, skipped)
+	assert.Nil(t, linter.LintEach([]string{filepath.Join(dir, "docs")}, "*", func(f *core.File) error {
+		return nil
+	}))
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, core.ErrorSkipped, skipped[0].Kind)
+	assert.Equal(t, "larger than 20 bytes", skipped[0].Message)
+
+	config.Include = []string{"*.md"}
+	config.Exclude = []string{"**/other/**"}
+	files, err = linter.Files([]string{filepath.Join(dir, "docs")}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"docs/a.md"}, rel(files))
+
+	// An invalid glob is an error, not a panic.
+	config.Include = []string{"[ab"}
+	_, err = linter.Files([]string{filepath.Join(dir, "docs")}, "*")
+	assert.EqualError(t, err, "invalid glob '[ab': unexpected end of input")
+	config.Include, config.Exclude = nil, []string{"[ab"}
+	err = linter.LintEach([]string{filepath.Join(dir, "docs")}, "*", func(f *core.File) error {
+		return nil
+	})
+	assert.EqualError(t, err, "invalid glob '[ab': unexpected end of input")
+
+	// We never ignore a path we're given explicitly.
+	config.Include, config.Exclude = nil, nil
+	files, err = linter.Files([]string{filepath.Join(dir, "docs", "node_modules")}, "*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"docs/node_modules/c.md"}, rel(files))
+}
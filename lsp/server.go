@@ -0,0 +1,276 @@
+/*
+Package lsp implements a Language Server Protocol server, allowing editors to
+lint open documents without writing them to disk.
+
+The server communicates over a pair of streams (typically stdin and stdout)
+and supports full-document synchronization, diagnostics, and quick fixes for
+the suggestions made by substitution checks.
+*/
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+)
+
+// A Server lints the documents opened by a client.
+type Server struct {
+	linter lint.Linter
+	docs   map[string]string // the current text of each open document
+	in     *bufio.Reader
+	out    io.Writer
+
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer creates a Server that reads from `in` and writes to `out`.
+func NewServer(linter lint.Linter, in io.Reader, out io.Writer) *Server {
+	return &Server{
+		linter: linter, docs: make(map[string]string),
+		in: bufio.NewReader(in), out: out}
+}
+
+// Run handles messages until the client exits or closes its stream.
+func (s *Server) Run() error {
+	for {
+		body, err := readMessage(s.in)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var req request
+		if err = json.Unmarshal(body, &req); err != nil {
+			if err = s.replyError(nil, parseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		} else if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit before shutdown")
+			}
+			return nil
+		}
+
+		if err = s.handle(req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a single request or notification, only returning an error
+// if we can't write to the client.
+func (s *Server) handle(req request) error {
+	if !s.initialized && req.Method != "initialize" {
+		if req.ID != nil {
+			return s.replyError(req.ID, serverNotInitialized, "not initialized")
+		}
+		return nil
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		return s.reply(req.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // Full
+				},
+				"codeActionProvider": true,
+			},
+		})
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(req.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			s.docs[params.TextDocument.URI] = params.TextDocument.Text
+			return s.publish(params.TextDocument.URI)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if json.Unmarshal(req.Params, &params) == nil && len(params.ContentChanges) > 0 {
+			// We only support full synchronization, so the last change
+			// holds the entire document.
+			last := params.ContentChanges[len(params.ContentChanges)-1]
+			s.docs[params.TextDocument.URI] = last.Text
+			return s.publish(params.TextDocument.URI)
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			delete(s.docs, params.TextDocument.URI)
+			return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+				URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+		}
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, invalidParams, err.Error())
+		}
+		return s.reply(req.ID, s.codeActions(params))
+	default:
+		if req.ID != nil {
+			return s.replyError(req.ID, methodNotFound, "unsupported method: "+req.Method)
+		}
+	}
+	return nil
+}
+
+// lint lints the document identified by `uri`, returning its text and alerts.
+func (s *Server) lint(uri string) (string, *core.File) {
+	text := s.docs[uri]
+	linted, _ := s.linter.LintStringAs(text, s.pathFromURI(uri))
+	return text, linted[0]
+}
+
+// publish sends the diagnostics for the document identified by `uri`.
+func (s *Server) publish(uri string) error {
+	text, f := s.lint(uri)
+	diagnostics := []diagnostic{}
+	for _, a := range f.SortedAlerts() {
+		diagnostics = append(diagnostics, toDiagnostic(text, a))
+	}
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI: uri, Diagnostics: diagnostics})
+}
+
+// codeActions converts the suggestions that overlap the given range into
+// quick fixes.
+func (s *Server) codeActions(params codeActionParams) []codeAction {
+	actions := []codeAction{}
+	uri := params.TextDocument.URI
+	if _, ok := s.docs[uri]; !ok {
+		return actions
+	}
+
+	text, f := s.lint(uri)
+	edits, _ := f.Edits([]byte(text))
+	for _, e := range edits {
+		r := textRange{Start: toPosition(text, e.Start), End: toPosition(text, e.End)}
+		if r.End.Line < params.Range.Start.Line || r.Start.Line > params.Range.End.Line {
+			continue
+		}
+		actions = append(actions, codeAction{
+			Title:       fmt.Sprintf("Replace with '%s'", e.Text),
+			Kind:        "quickfix",
+			Diagnostics: []diagnostic{toDiagnostic(text, e.Alert)},
+			Edit: workspaceEdit{Changes: map[string][]textEdit{
+				uri: {{Range: r, NewText: e.Text}}}},
+		})
+	}
+	return actions
+}
+
+// pathFromURI converts a document URI into the path we lint it as, which
+// determines its format and the config sections that apply to it.
+func (s *Server) pathFromURI(uri string) string {
+	path := uri
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		path = u.Path
+		if runtime.GOOS == "windows" {
+			path = filepath.FromSlash(strings.TrimPrefix(path, "/"))
+		}
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	if filepath.Ext(path) == "" {
+		// e.g., an unsaved document.
+		path += s.linter.Config.InExt
+	}
+	return path
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return s.replyError(id, invalidParams, err.Error())
+	}
+	raw := json.RawMessage(b)
+	return writeMessage(s.out, response{JSONRPC: "2.0", ID: id, Result: &raw})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, msg string) error {
+	return writeMessage(s.out, response{
+		JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: msg}})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, notification{
+		JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// toDiagnostic converts an Alert into an LSP diagnostic.
+func toDiagnostic(text string, a core.Alert) diagnostic {
+	line := lineAt(text, a.Line-1)
+	return diagnostic{
+		Range: textRange{
+			Start: position{Line: a.Line - 1, Character: utf16Length(line, a.Span[0]-1)},
+			End:   position{Line: a.Line - 1, Character: utf16Length(line, a.Span[1])},
+		},
+		Severity: severityToLSP[a.Severity],
+		Code:     a.Check,
+		Source:   "vale",
+		Message:  a.Message,
+	}
+}
+
+// toPosition converts a byte offset within `text` into an LSP position.
+func toPosition(text string, offset int) position {
+	pos := position{}
+	start := 0
+	for i := 0; i < offset && i < len(text); i++ {
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			continue // The line ends at the "\n".
+		} else if text[i] == '\n' || text[i] == '\r' {
+			pos.Line++
+			start = i + 1
+		}
+	}
+	pos.Character = utf16Length(text[start:offset], -1)
+	return pos
+}
+
+// lineAt returns the given (0-based) line of `text`.
+func lineAt(text string, n int) string {
+	lines := strings.Split(core.PrepText(text), "\n")
+	if n < 0 || n >= len(lines) {
+		return ""
+	}
+	return lines[n]
+}
+
+// utf16Length returns the number of UTF-16 code units in the first `runes`
+// runes of `s` (or all of `s`, if `runes` is negative).
+func utf16Length(s string, runes int) int {
+	units := 0
+	for i, r := range []rune(s) {
+		if i == runes {
+			break
+		}
+		if r >= 0x10000 {
+			units += 2 // a surrogate pair
+		} else {
+			units++
+		}
+	}
+	return units
+}
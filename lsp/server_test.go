@@ -0,0 +1,86 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func frame(t *testing.T, msgs ...string) io.Reader {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	return &buf
+}
+
+func readAll(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	msgs := []map[string]interface{}{}
+	r := bufio.NewReader(out)
+	for {
+		body, err := readMessage(r)
+		if err == io.EOF {
+			return msgs
+		}
+		assert.Nil(t, err)
+
+		var msg map[string]interface{}
+		assert.Nil(t, json.Unmarshal(body, &msg))
+		msgs = append(msgs, msg)
+	}
+}
+
+func TestServer(t *testing.T) {
+	var out bytes.Buffer
+
+	config := core.NewConfig()
+	config.InExt = ".txt"
+	linter := lint.Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	in := frame(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/test.md","languageId":"markdown","version":1,"text":"😀 This is very good.\nNot many people `+"`very`"+` know.\n"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":{"textDocument":{"uri":"file:///tmp/test.md"},"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":3}},"context":{"diagnostics":[]}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	assert.Nil(t, NewServer(linter, in, &out).Run())
+
+	msgs := readAll(t, &out)
+	assert.Len(t, msgs, 4)
+
+	diagnostics := msgs[1]["params"].(map[string]interface{})["diagnostics"].([]interface{})
+	assert.Len(t, diagnostics, 2)
+
+	// "😀" is a single rune, but two UTF-16 code units.
+	very := diagnostics[0].(map[string]interface{})
+	assert.Equal(t, "vale.Editorializing", very["code"])
+	assert.Equal(t, map[string]interface{}{
+		"start": map[string]interface{}{"line": 0.0, "character": 11.0},
+		"end":   map[string]interface{}{"line": 0.0, "character": 15.0},
+	}, very["range"])
+
+	actions := msgs[2]["result"].([]interface{})
+	assert.Len(t, actions, 1)
+
+	edit := actions[0].(map[string]interface{})["edit"].(map[string]interface{})
+	changes := edit["changes"].(map[string]interface{})["file:///tmp/test.md"].([]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 1.0, "character": 0.0},
+			"end":   map[string]interface{}{"line": 1.0, "character": 8.0},
+		},
+		"newText": "Few",
+	}, changes[0])
+
+	assert.Nil(t, msgs[3]["result"])
+}
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/ValeLint/vale/check"
 	"github.com/ValeLint/vale/core"
 	"github.com/ValeLint/vale/lint"
+	"github.com/ValeLint/vale/lsp"
+	"github.com/ValeLint/vale/server"
 	"github.com/ValeLint/vale/ui"
 	"github.com/urfave/cli"
 )
@@ -17,25 +21,52 @@ import (
 // version is set during the release build process.
 var version = "master"
 
+// errRuntime signals that something (e.g., a missing markup converter) kept
+// us from fully linting our input, which we report with exit code 2 -- as
+// opposed to 1 for lint errors.
+var errRuntime = cli.NewExitError("", 2)
+
+// profileSize is the number of rules and files that `--profile` reports.
+const profileSize = 10
+
 func main() {
-	var glob string
+	var glob, diffRev, baselinePath string
+	var diffStdin, watch bool
 
-	config := core.LoadConfig()
+	config := core.NewConfig()
+	// Unlike an embedder (see the api package), we respect the ignore files
+	// of the directory we're run from.
+	config.WorkDir = "."
 	app := cli.NewApp()
 	app.Name = "vale"
 	app.Usage = "A command-line linter for prose."
 	app.Version = version
 	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:        "config",
+			Usage:       "load the config `file` at this path (or $VALE_CONFIG_PATH)",
+			Destination: &config.Path,
+		},
 		cli.StringFlag{
 			Name:        "glob",
 			Value:       "*",
 			Usage:       `a glob pattern (e.g., --glob='*.{md,txt}')`,
 			Destination: &glob,
 		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only lint files that match one of these globs (repeatable)",
+			Value: (*cli.StringSlice)(&config.Include),
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "skip files that match any of these globs (repeatable)",
+			Value: (*cli.StringSlice)(&config.Exclude),
+		},
 		cli.StringFlag{
 			Name:        "output",
 			Value:       "CLI",
-			Usage:       `output style ("line" or "JSON")`,
+			Usage:       `output style ("line", "JSON", "jsonl", "SARIF", "JUnit", "checkstyle", "html" or the path to a .tmpl file)`,
 			Destination: &config.Output,
 		},
 		cli.StringFlag{
@@ -74,6 +105,69 @@ func main() {
 			Usage:       "return relative paths",
 			Destination: &config.Relative,
 		},
+		cli.BoolFlag{
+			Name:        "metrics",
+			Usage:       "include document statistics in JSON output",
+			Destination: &config.Metrics,
+		},
+		cli.BoolFlag{
+			Name:        "strict",
+			Usage:       "fail on any problem with the loaded rules",
+			Destination: &config.Strict,
+		},
+		cli.BoolFlag{
+			Name:        "profile",
+			Usage:       "report the time spent in each rule and file (on stderr)",
+			Destination: &config.Profile,
+		},
+		cli.IntFlag{
+			Name:        "workers",
+			Usage:       "lint up to `n` files at once (default: the number of CPUs)",
+			Destination: &config.Workers,
+		},
+		cli.DurationFlag{
+			Name:        "timeout",
+			Usage:       "stop linting a file after `duration` (e.g., \"30s\") and report it as an error",
+			Destination: &config.Timeout,
+		},
+		cli.BoolFlag{
+			Name:        "watch",
+			Usage:       "re-lint files as they change",
+			Destination: &watch,
+		},
+		cli.StringFlag{
+			Name:        "diff",
+			Usage:       "only report alerts on lines changed since a git revision",
+			Destination: &diffRev,
+		},
+		cli.BoolFlag{
+			Name:        "diff-stdin",
+			Usage:       "only report alerts on lines changed by a unified diff read from stdin",
+			Destination: &diffStdin,
+		},
+		cli.StringFlag{
+			Name:        "cache-dir",
+			Usage:       "cache results in `dir`, so that unchanged files aren't linted again",
+			Destination: &config.CacheDir,
+		},
+		cli.StringFlag{
+			Name:        "baseline",
+			Usage:       "hide the alerts recorded in a baseline `file`",
+			Destination: &baselinePath,
+		},
+	}
+	app.Before = func(c *cli.Context) error {
+		// We can only load our config file once we know the command-line
+		// options, but `Before` shows the usage with any errors it returns.
+		loaded, err := core.ReloadConfig(config)
+		if !core.CheckError(err) {
+			// Like any other runtime failure (see errRuntime).
+			os.Exit(2)
+		}
+		config = loaded
+		// HandleExitCoder prints the error and exits with its code.
+		cli.HandleExitCoder(checkGlobs(config, glob))
+		return nil
 	}
 	app.Commands = []cli.Command{
 		{
@@ -85,6 +179,45 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "ls-rules",
+			Usage: "Lists every loaded rule and the config sections that enable it",
+			Action: func(c *cli.Context) error {
+				mgr := check.NewManager(config)
+				ui.PrintRules(mgr.Rules(), config.Output == "JSON")
+				return nil
+			},
+		},
+		{
+			Name:      "test",
+			Usage:     "Tests each rule on a StylesPath against its examples",
+			ArgsUsage: "<StylesPath>",
+			Action: func(c *cli.Context) error {
+				path := c.Args().First()
+				if path == "" {
+					path = config.StylesPath
+				}
+				if path == "" {
+					return errors.New("no StylesPath given")
+				}
+				return testStyles(path)
+			},
+		},
+		{
+			Name:      "validate",
+			Usage:     "Reports every problem with the rules on a StylesPath",
+			ArgsUsage: "[StylesPath]",
+			Action: func(c *cli.Context) error {
+				path := c.Args().First()
+				if path == "" {
+					path = config.StylesPath
+				}
+				if path == "" {
+					return errors.New("no StylesPath given")
+				}
+				return validateStyles(path)
+			},
+		},
 		{
 			Name:  "new",
 			Usage: "Generates a template for the given extension point",
@@ -100,39 +233,202 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "lsp",
+			Usage: "Starts a Language Server Protocol server over stdio",
+			Action: func(c *cli.Context) error {
+				linter, err := newLinter(config)
+				if err != nil {
+					return err
+				}
+				return lsp.NewServer(linter, os.Stdin, os.Stdout).Run()
+			},
+		},
+		{
+			Name:  "serve",
+			Usage: "Starts an HTTP server that lints the text POSTed to /lint",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "addr",
+					Value: "localhost:7777",
+					Usage: "the `address` to listen on",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				linter, err := newLinter(config)
+				if err != nil {
+					return err
+				}
+				handler := server.NewServer(linter)
+				fmt.Fprintf(os.Stderr, "Listening on %s\n", c.String("addr"))
+				return http.ListenAndServe(c.String("addr"), handler)
+			},
+		},
+		{
+			Name:  "baseline",
+			Usage: "Manages a baseline of known alerts",
+			Subcommands: []cli.Command{
+				{
+					Name:      "create",
+					Usage:     "Records every current alert in a baseline file",
+					ArgsUsage: "[paths...]",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "file",
+							Value: ".vale-baseline.json",
+							Usage: "the `file` to write",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						linter, err := newLinter(config)
+						if err != nil {
+							return err
+						}
+						linted, err := linter.Lint(c.Args(), glob)
+						if err != nil {
+							return err
+						}
+						return core.WriteBaseline(c.String("file"), linted)
+					},
+				},
+			},
+		},
+		{
+			Name:      "stats",
+			Usage:     "Reports document statistics and readability metrics",
+			ArgsUsage: "[paths...]",
+			Action: func(c *cli.Context) error {
+				linter, err := newLinter(config)
+				if err != nil {
+					return err
+				}
+				linted, err := linter.Lint(c.Args(), glob)
+				if err != nil {
+					return err
+				}
+				ui.PrintStats(linted, config.Output == "JSON")
+				return nil
+			},
+		},
+		{
+			Name:      "fix",
+			Usage:     "Applies the suggestions of substitution checks in place",
+			ArgsUsage: "[paths...]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "print a unified diff instead of writing to files",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				linter, err := newLinter(config)
+				if err != nil {
+					return err
+				}
+				linted, err := linter.Lint(c.Args(), glob)
+				if err != nil {
+					return err
+				}
+				return fixFiles(linted, c.Bool("dry-run"))
+			},
+		},
 	}
 
 	app.Action = func(c *cli.Context) error {
 		var linted []*core.File
+		var changes core.Diff
+		var baseline core.Baseline
 		var err error
-		var hasAlerts bool
+		var hasAlerts, hasErrors bool
 
-		if c.NArg() > 0 || core.Stat() {
-			linter := lint.Linter{
-				Config: config, CheckManager: check.NewManager(config)}
+		args := []string(c.Args())
+		if baselinePath != "" {
+			if baseline, err = core.LoadBaseline(baselinePath); err != nil {
+				return err
+			}
+		}
 
-			if c.NArg() > 0 {
-				if core.LooksLikeStdin(c.Args()[0]) {
-					linted, err = linter.LintString(c.Args()[0])
+		if watch && (diffStdin || diffRev != "") {
+			// A diff is only read once, so it'd soon be out of date.
+			return errors.New("--watch can't be combined with --diff or --diff-stdin")
+		}
+
+		if diffStdin {
+			changes, err = core.DiffFromStdin()
+		} else if diffRev != "" {
+			changes, err = core.GitDiff(diffRev)
+		}
+		if err != nil {
+			return err
+		} else if changes != nil && len(args) == 0 {
+			// If we're not given any paths, we lint the diff's files.
+			args = changes.Paths()
+		}
+
+		if watch {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			return watchFiles(config, args, glob, baseline)
+		}
+
+		if len(args) > 0 || changes != nil || core.Stat() {
+			linter, err := newLinter(config)
+			if err != nil {
+				return err
+			}
+
+			// We print each file as soon as it's linted, holding on to them
+			// only if our quality gates need them.
+			gates := config.Gates
+			printer, err := newPrinter(linter)
+			if err != nil {
+				return err
+			}
+			handle := func(f *core.File) error {
+				if changes != nil {
+					changes.Filter(f)
+				}
+				if baseline != nil {
+					baseline.Filter(f)
+				}
+				if gates.Enabled() {
+					linted = append(linted, f)
+				}
+				hasErrors = printErrors(f, config) || hasErrors
+				return printer.Print(f)
+			}
+
+			if changes == nil && (len(args) == 0 || core.LooksLikeStdin(args[0])) {
+				var src string
+				if len(args) > 0 {
+					src = args[0]
 				} else {
-					linted, err = linter.Lint(c.Args(), glob)
+					stdin, _ := ioutil.ReadAll(os.Stdin)
+					src = string(stdin)
 				}
+				files, _ := linter.LintString(src)
+				err = handle(files[0])
 			} else {
-				stdin, _ := ioutil.ReadAll(os.Stdin)
-				linted, err = linter.LintString(string(stdin))
+				err = linter.LintEach(args, glob, handle)
 			}
 
-			// How should we style the output?
-			if config.Output == "line" {
-				hasAlerts = ui.PrintLineAlerts(linted, config.Relative)
-			} else if config.Output == "JSON" {
-				hasAlerts = ui.PrintJSONAlerts(linted)
-			} else {
-				hasAlerts = ui.PrintVerboseAlerts(linted, config.Wrap)
+			var printErr error
+			if hasAlerts, printErr = printer.Close(); printErr != nil {
+				return printErr
+			}
+			if gates.Enabled() {
+				// With MaxErrors set, errors alone don't fail a run.
+				hasAlerts = (hasAlerts && gates.MaxErrors < 0) || printGates(linted, config)
+			}
+			if linter.Profile != nil {
+				ui.PrintProfile(os.Stderr, linter.Profile, profileSize)
 			}
 
 			// Should return a nonzero vale on errors?
-			if err == nil && hasAlerts && !config.NoExit {
+			if err == nil && (hasErrors || len(linter.CheckManager.Errors) > 0) {
+				err = errRuntime
+			} else if err == nil && hasAlerts && !config.NoExit {
 				err = errors.New("")
 			}
 
@@ -150,3 +446,187 @@ func main() {
 		os.Exit(0)
 	}
 }
+
+// checkGlobs reports the first invalid pattern among `glob` and our config's
+// Include and Exclude globs.
+func checkGlobs(config *core.Config, glob string) error {
+	patterns := append([]string{glob}, config.Include...)
+	for _, pat := range append(patterns, config.Exclude...) {
+		if _, err := core.CompileGlob(pat); err != nil {
+			return cli.NewExitError(err.Error(), 2)
+		}
+	}
+	return nil
+}
+
+// newLinter loads the rules specified by `config`, along with our cache (if
+// any). In strict mode, we print every problem with the rules and return an
+// error.
+//
+// Any rules we couldn't load are reported on stderr, except with JSON output,
+// which includes them.
+func newLinter(config *core.Config) (lint.Linter, error) {
+	mgr := check.NewManager(config)
+	if config.Output != "JSON" {
+		for _, e := range mgr.Errors {
+			fmt.Fprintln(os.Stderr, e.Error())
+		}
+	}
+	for _, e := range mgr.RuleErrors {
+		fmt.Fprintln(os.Stderr, e.Error())
+	}
+	if n := len(mgr.RuleErrors); n > 0 {
+		msg := fmt.Sprintf("found %d %s in our rules", n, ui.Pluralize("problem", n))
+		return lint.Linter{}, cli.NewExitError(msg, 2)
+	}
+
+	linter := lint.Linter{Config: config, CheckManager: mgr, Skipped: printSkipped}
+	if config.CacheDir != "" {
+		cache, err := lint.NewCache(config.CacheDir, config, version)
+		if err != nil {
+			return lint.Linter{}, err
+		}
+		linter.Cache = cache
+	}
+	if config.Profile {
+		linter.Profile = core.NewProfile()
+	}
+	return linter, nil
+}
+
+// validateStyles prints every problem with the rules on `stylesPath`.
+func validateStyles(stylesPath string) error {
+	rules, err := check.FindRules(stylesPath)
+	if err != nil {
+		return err
+	}
+
+	paths := []string{}
+	for _, fp := range rules {
+		paths = append(paths, fp)
+	}
+	sort.Strings(paths)
+
+	count := 0
+	for _, fp := range paths {
+		src, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return err
+		}
+		for _, e := range check.ValidateRule(fp, src) {
+			fmt.Println(e.Error())
+			count++
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("found %d %s in %d %s", count, ui.Pluralize("problem", count),
+			len(paths), ui.Pluralize("rule", len(paths)))
+	}
+	fmt.Printf("No problems found in %d %s.\n", len(paths), ui.Pluralize("rule", len(paths)))
+	return nil
+}
+
+// applyBaseline removes the alerts in `linted` that are recorded in
+// `baseline` (if any).
+func applyBaseline(linted []*core.File, baseline core.Baseline) {
+	if baseline == nil {
+		return
+	}
+	for _, f := range linted {
+		baseline.Filter(f)
+	}
+}
+
+// printGates prints the results of the quality gates set in `config`,
+// returning whether or not any of them failed. The summary follows the alerts
+// in the CLI style, but goes to stderr otherwise to keep stdout parseable.
+func printGates(linted []*core.File, config *core.Config) bool {
+	w := os.Stderr
+	if config.Output == "CLI" {
+		w = os.Stdout
+	}
+	return ui.PrintGates(w, config.Gates.Check(linted))
+}
+
+// printAlerts prints the alerts in `linted` in the style given by the linter's
+// config, returning whether or not there were any errors.
+func printAlerts(linted []*core.File, linter lint.Linter) (bool, error) {
+	for _, f := range linted {
+		printErrors(f, linter.Config)
+	}
+	printer, err := newPrinter(linter)
+	if err != nil {
+		return false, err
+	}
+	return ui.PrintAll(printer, linted)
+}
+
+// printSkipped notes that we didn't lint a file (e.g., because it's too
+// large), which isn't an error.
+func printSkipped(e core.Error) {
+	fmt.Fprintf(os.Stderr, "skipping '%s': %s\n", e.Path, e.Message)
+}
+
+// printErrors prints the problems that kept us from fully linting `f` (e.g.,
+// a missing markup converter) to stderr -- unless we're using JSON output,
+// which includes them -- returning whether or not there were any.
+func printErrors(f *core.File, config *core.Config) bool {
+	if config.Output != "JSON" {
+		for _, err := range f.Errors {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	return len(f.Errors) > 0
+}
+
+// newPrinter creates a printer for the output style given by the linter's
+// config. An output template is parsed here, so that any problem with it is
+// reported before we start linting.
+func newPrinter(linter lint.Linter) (ui.Printer, error) {
+	var printer ui.Printer
+
+	config := linter.Config
+	if config.Output == "line" {
+		printer = ui.NewLinePrinter(config.Relative)
+	} else if config.Output == "jsonl" {
+		printer = ui.NewJSONLinesPrinter()
+	} else if config.Output == "JSON" {
+		printer = batchPrinter(func(linted []*core.File) bool {
+			return ui.PrintJSONAlerts(linted, config.Metrics, linter.CheckManager.Errors)
+		})
+	} else if config.Output == "SARIF" {
+		printer = batchPrinter(func(linted []*core.File) bool {
+			return ui.PrintSARIFAlerts(linted, linter.CheckManager.AllChecks, version)
+		})
+	} else if config.Output == "JUnit" {
+		printer = batchPrinter(ui.PrintJUnitAlerts)
+	} else if config.Output == "checkstyle" {
+		printer = batchPrinter(ui.PrintCheckstyleAlerts)
+	} else if config.Output == "html" {
+		printer = batchPrinter(ui.PrintHTMLAlerts)
+	} else if ui.IsTemplate(config.Output) {
+		tmpl, err := ui.ParseTemplate(config.Output)
+		if err != nil {
+			return nil, err
+		}
+		printer = ui.NewBufferedPrinter(func(linted []*core.File) (bool, error) {
+			return ui.PrintTemplateAlerts(linted, tmpl)
+		})
+	} else {
+		printer = ui.NewVerbosePrinter(config.Wrap)
+	}
+
+	if config.Sorted {
+		printer = ui.NewSortedPrinter(printer)
+	}
+	return printer, nil
+}
+
+// batchPrinter creates a printer for an output style that needs every file
+// at once.
+func batchPrinter(print func([]*core.File) bool) ui.Printer {
+	return ui.NewBufferedPrinter(func(linted []*core.File) (bool, error) {
+		return print(linted), nil
+	})
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+	"github.com/ValeLint/vale/ui"
+	"gopkg.in/yaml.v2"
+)
+
+// A ruleExample is a snippet of text that a rule should (or shouldn't) raise
+// alerts on.
+//
+// In a rule's YAML, an example is either a string or a map that also lists
+// the alerts we expect:
+//
+//	examples:
+//	  valid:
+//	    - This is fine.
+//	  invalid:
+//	    - text: We utilize it.
+//	      alerts:
+//	        - line: 1
+//	          span: [4, 10]
+//	          message: Use 'use' instead of 'utilize'.
+type ruleExample struct {
+	Text   string
+	Alerts []expectedAlert
+}
+
+// An expectedAlert describes an alert we expect a rule to raise. Any
+// (non-zero) field is compared to the actual alert.
+type expectedAlert struct {
+	Line    int
+	Span    []int
+	Message string
+}
+
+// UnmarshalYAML allows an example to be given as a plain string.
+func (e *ruleExample) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&e.Text); err == nil {
+		return nil
+	}
+	var example struct {
+		Text   string
+		Alerts []expectedAlert
+	}
+	if err := unmarshal(&example); err != nil {
+		return err
+	}
+	e.Text, e.Alerts = example.Text, example.Alerts
+	return nil
+}
+
+type ruleExamples struct {
+	Examples struct {
+		Valid   []ruleExample
+		Invalid []ruleExample
+	}
+}
+
+// testStyles runs every rule on `stylesPath` against its examples -- both
+// those in its YAML and those in its style's fixtures directory (e.g.,
+// "Style/fixtures/Rule/invalid.md").
+func testStyles(stylesPath string) error {
+	stylesPath, err := filepath.Abs(stylesPath)
+	if err != nil {
+		return err
+	} else if !core.IsDir(stylesPath) {
+		return fmt.Errorf("'%s' is not a directory", stylesPath)
+	}
+
+	rules, err := check.FindRules(stylesPath)
+	if err != nil {
+		return err
+	}
+
+	// We load every style at once, keeping only the alerts of the rule
+	// we're testing.
+	config := core.NewConfig()
+	config.StylesPath = stylesPath
+	config.MinAlertLevel = 0
+	config.InExt = ".md"
+	config.GBaseStyles = []string{}
+	for name := range rules {
+		style := strings.Split(name, ".")[0]
+		if !core.StringInSlice(style, config.GBaseStyles) {
+			config.GBaseStyles = append(config.GBaseStyles, style)
+		}
+	}
+	linter := lint.Linter{Config: config, CheckManager: check.NewManager(config)}
+
+	names := []string{}
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tested, failed := 0, 0
+	for _, name := range names {
+		failures, count := testRule(linter, name, rules[name])
+		if count == 0 {
+			continue
+		}
+		tested += count
+		if len(failures) > 0 {
+			failed += len(failures)
+			fmt.Printf("FAIL %s\n", name)
+			for _, f := range failures {
+				fmt.Printf("     %s\n", f)
+			}
+		} else {
+			fmt.Printf("ok   %s (%d %s)\n", name, count, ui.Pluralize("example", count))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, tested, ui.Pluralize("example", tested))
+	} else if tested == 0 {
+		return errors.New("no examples found")
+	}
+	return nil
+}
+
+// testRule runs the rule `name` against its examples, returning a
+// description of each failure and the number of examples.
+func testRule(linter lint.Linter, name, path string) ([]string, int) {
+	var examples ruleExamples
+
+	failures := []string{}
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = yaml.Unmarshal(b, &examples)
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %s", relPath(path), err)}, 1
+	}
+	count := len(examples.Examples.Valid) + len(examples.Examples.Invalid)
+
+	// We never read an example from disk, even if it looks like a path
+	// (e.g., "README.md").
+	example := "example" + linter.Config.InExt
+	for i, e := range examples.Examples.Valid {
+		linted, _ := linter.LintStringAs(e.Text, example)
+		if got := alertsFor(linted, name); len(got) > 0 {
+			failures = append(failures, fmt.Sprintf(
+				"valid example %d (%q): unexpected alert at %s",
+				i+1, e.Text, describeAlert(got[0])))
+		}
+	}
+
+	for i, e := range examples.Examples.Invalid {
+		linted, _ := linter.LintStringAs(e.Text, example)
+		prefix := fmt.Sprintf("invalid example %d (%q)", i+1, e.Text)
+		failures = append(failures, compareAlerts(prefix, e.Alerts, alertsFor(linted, name))...)
+	}
+
+	fixtures := filepath.Join(filepath.Dir(path), "fixtures", strings.Split(name, ".")[1])
+	files, _ := ioutil.ReadDir(fixtures)
+	for _, fi := range files {
+		valid := strings.HasPrefix(fi.Name(), "valid")
+		if fi.IsDir() || !(valid || strings.HasPrefix(fi.Name(), "invalid")) {
+			continue
+		}
+		count++
+
+		fp := relPath(filepath.Join(fixtures, fi.Name()))
+		linted, err := linter.Lint([]string{fp}, "*")
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", fp, err))
+			continue
+		}
+
+		got := alertsFor(linted, name)
+		if valid && len(got) > 0 {
+			failures = append(failures, fmt.Sprintf(
+				"%s: unexpected alert at %s", fp, describeAlert(got[0])))
+		} else if !valid && len(got) == 0 {
+			failures = append(failures, fmt.Sprintf("%s: expected an alert", fp))
+		}
+	}
+
+	return failures, count
+}
+
+// alertsFor returns the alerts raised by the rule `name` in `linted`.
+func alertsFor(linted []*core.File, name string) []core.Alert {
+	alerts := []core.Alert{}
+	for _, f := range linted {
+		for _, a := range f.SortedAlerts() {
+			if a.Check == name {
+				alerts = append(alerts, a)
+			}
+		}
+	}
+	return alerts
+}
+
+// compareAlerts compares the alerts a rule raised on an invalid example
+// with those we expected -- or, if we weren't given any, checks that there
+// was at least one.
+func compareAlerts(prefix string, expected []expectedAlert, got []core.Alert) []string {
+	if len(expected) == 0 {
+		if len(got) == 0 {
+			return []string{prefix + ": expected an alert"}
+		}
+		return nil
+	} else if len(expected) != len(got) {
+		return []string{fmt.Sprintf(
+			"%s: expected %d %s, got %d", prefix, len(expected),
+			ui.Pluralize("alert", len(expected)), len(got))}
+	}
+
+	failures := []string{}
+	for i, want := range expected {
+		a := got[i]
+		if (want.Line != 0 && want.Line != a.Line) ||
+			(want.Span != nil && !reflect.DeepEqual(want.Span, a.Span)) ||
+			(want.Message != "" && want.Message != a.Message) {
+			failures = append(failures, fmt.Sprintf(
+				"%s: alert %d is %s", prefix, i+1, describeAlert(a)))
+		}
+	}
+	return failures
+}
+
+// relPath returns `path` relative to the current directory, if it's within
+// it.
+func relPath(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
+
+func describeAlert(a core.Alert) string {
+	return fmt.Sprintf("%d:%v (%q)", a.Line, a.Span, a.Message)
+}
@@ -0,0 +1,154 @@
+/*
+Package server implements Vale's HTTP service mode, which keeps a Linter (and
+its loaded styles) in memory between requests.
+
+It exposes two endpoints:
+
+	POST /lint   lints the given text and returns its alerts (and any errors)
+	GET  /rules  lists the loaded checks
+*/
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+)
+
+// maxBodySize is the largest request body we'll accept.
+const maxBodySize = 10 << 20
+
+// A Server handles HTTP requests using a single Linter.
+type Server struct {
+	linter lint.Linter
+	mux    *http.ServeMux
+}
+
+// A LintRequest is the body of a request to `/lint`.
+type LintRequest struct {
+	Text   string `json:"text"`   // the content to lint
+	Format string `json:"format"` // an extension, such as "md" or ".rst"
+	Path   string `json:"path"`   // (optional) used to match config sections
+}
+
+// A LintResponse is the body of a response from `/lint`.
+type LintResponse struct {
+	Path   string       `json:"path"`
+	Alerts []core.Alert `json:"alerts"`
+	Errors []core.Error `json:"errors,omitempty"` // problems that kept us from fully linting the text
+}
+
+// A Rule describes a loaded check in a response from `/rules`.
+type Rule struct {
+	Name        string `json:"name"`
+	Extends     string `json:"extends"`
+	Level       string `json:"level"`
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+// NewServer creates a Server for the given Linter.
+//
+// If any of its checks need our part-of-speech tagger, we initialize it up
+// front so that the first request doesn't pay for its (slow) start-up.
+func NewServer(linter lint.Linter) *Server {
+	if linter.CheckManager.UsesPOS {
+		core.LoadTagger()
+	}
+
+	s := &Server{linter: linter, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/lint", s.handleLint)
+	s.mux.HandleFunc("/rules", s.handleRules)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleLint(w http.ResponseWriter, r *http.Request) {
+	var req LintRequest
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "expected a POST request")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if req.Format == "" && filepath.Ext(req.Path) == "" {
+		writeError(w, http.StatusBadRequest, "expected a format or a path with an extension")
+		return
+	}
+
+	path := lintPath(req.Path, req.Format)
+
+	linted, err := s.linter.LintStringAs(req.Text, path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	alerts := linted[0].SortedAlerts()
+	if alerts == nil {
+		alerts = []core.Alert{}
+	}
+	writeJSON(w, http.StatusOK, LintResponse{Path: path, Alerts: alerts, Errors: linted[0].Errors})
+}
+
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "expected a GET request")
+		return
+	}
+
+	rules := []Rule{}
+	for name, chk := range s.linter.CheckManager.AllChecks {
+		level := ""
+		for l, n := range core.LevelToInt {
+			if n == chk.Level {
+				level = l
+			}
+		}
+		rules = append(rules, Rule{
+			Name: name, Extends: chk.Extends, Level: level,
+			Scope: chk.Scope.Value, Description: chk.Description,
+			Link: chk.Link})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	writeJSON(w, http.StatusOK, rules)
+}
+
+// lintPath determines the path we lint a request's text as: `path` (if
+// given) with the extension of `format` (if given).
+func lintPath(path, format string) string {
+	if format == "" {
+		return path
+	} else if !strings.HasPrefix(format, ".") {
+		format = "." + format
+	}
+	if path == "" {
+		return "stdin" + format
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + format
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	core.CheckError(json.NewEncoder(w).Encode(v))
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
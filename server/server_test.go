@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *Server {
+	config := core.NewConfig()
+	return NewServer(lint.Linter{Config: config, CheckManager: check.NewManager(config)})
+}
+
+func TestLint(t *testing.T) {
+	var resp LintResponse
+
+	body := `{"text": "This is *very* good.\n\n    very\n", "format": "md", "path": "docs/intro.txt"}`
+	rec := httptest.NewRecorder()
+	newTestServer().ServeHTTP(rec, httptest.NewRequest("POST", "/lint", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "docs/intro.md", resp.Path)
+	assert.Len(t, resp.Alerts, 1)
+	assert.Equal(t, "vale.Editorializing", resp.Alerts[0].Check)
+	assert.Equal(t, []int{10, 13}, resp.Alerts[0].Span)
+}
+
+func TestLintWithMissingConverter(t *testing.T) {
+	var resp LintResponse
+
+	dir, err := ioutil.TempDir("", "converter")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A PATH without rst2html.
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", dir)
+
+	body := `{"text": "It is very good.\n", "format": "rst"}`
+	rec := httptest.NewRecorder()
+	newTestServer().ServeHTTP(rec, httptest.NewRequest("POST", "/lint", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Alerts)
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, core.ErrorConverter, resp.Errors[0].Kind)
+	assert.Equal(t, "stdin.rst: rst2html not found", resp.Errors[0].Error())
+}
+
+func TestLintErrors(t *testing.T) {
+	s := newTestServer()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/lint", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/lint", strings.NewReader(`{"text": 1}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/lint", strings.NewReader(`{"text": "very"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRules(t *testing.T) {
+	var rules []Rule
+
+	rec := httptest.NewRecorder()
+	newTestServer().ServeHTTP(rec, httptest.NewRequest("GET", "/rules", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &rules))
+	assert.NotEmpty(t, rules)
+	for _, r := range rules {
+		if r.Name == "vale.Editorializing" {
+			assert.Equal(t, "existence", r.Extends)
+			assert.Equal(t, "warning", r.Level)
+			return
+		}
+	}
+	t.Error("vale.Editorializing not found")
+}
+
+func TestLintPath(t *testing.T) {
+	assert.Equal(t, "stdin.md", lintPath("", "md"))
+	assert.Equal(t, "a/b.rst", lintPath("a/b.txt", ".rst"))
+	assert.Equal(t, "a/b.txt", lintPath("a/b.txt", ""))
+}
+
+func TestConcurrentLint(t *testing.T) {
+	s := newTestServer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp LintResponse
+
+			body := `{"text": "It is very good.\n\n<!-- vale off -->\n\nvery\n", "format": "md"}`
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest("POST", "/lint", strings.NewReader(body)))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Len(t, resp.Alerts, 1)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// severityToCheckstyle maps Vale's alert levels to Checkstyle's severities.
+var severityToCheckstyle = map[string]string{
+	"suggestion": "info",
+	"warning":    "warning",
+	"error":      "error",
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// PrintCheckstyleAlerts prints Alerts in Checkstyle's XML format.
+func PrintCheckstyleAlerts(linted []*core.File) bool {
+	alertCount := 0
+	report := checkstyleReport{Version: "5.0"}
+	for _, f := range linted {
+		file := checkstyleFile{Name: f.Path}
+		for _, a := range f.SortedAlerts() {
+			if a.Severity == "error" {
+				alertCount++
+			}
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     a.Line,
+				Column:   a.Span[0],
+				Severity: severityToCheckstyle[a.Severity],
+				Message:  fixOutputSpacing(a.Message),
+				Source:   a.Check,
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println(xml.Header + string(b))
+	}
+	return alertCount != 0
+}
@@ -16,35 +16,46 @@ const (
 	underlineColor                  = color.Underline
 )
 
-// PrintVerboseAlerts prints Alerts in verbose format.
-func PrintVerboseAlerts(linted []*core.File, wrap bool) bool {
-	var errors, warnings, suggestions int
-	var e, w, s int
-	var symbol string
+type verbosePrinter struct {
+	wrap                          bool
+	files                         int
+	errors, warnings, suggestions int
+}
 
-	for _, f := range linted {
-		e, w, s = printVerboseAlert(f, wrap)
-		errors += e
-		warnings += w
-		suggestions += s
-	}
+// NewVerbosePrinter creates a Printer that prints Alerts in verbose format,
+// followed by a summary.
+func NewVerbosePrinter(wrap bool) Printer {
+	return &verbosePrinter{wrap: wrap}
+}
+
+func (p *verbosePrinter) Print(f *core.File) error {
+	e, w, s := printVerboseAlert(f, p.wrap)
+	p.errors += e
+	p.warnings += w
+	p.suggestions += s
+	p.files++
+	return nil
+}
+
+func (p *verbosePrinter) Close() (bool, error) {
+	var symbol string
 
-	etotal := fmt.Sprintf("%d %s", errors, pluralize("error", errors))
-	wtotal := fmt.Sprintf("%d %s", warnings, pluralize("warning", warnings))
-	stotal := fmt.Sprintf("%d %s", suggestions, pluralize("suggestion", suggestions))
+	etotal := fmt.Sprintf("%d %s", p.errors, Pluralize("error", p.errors))
+	wtotal := fmt.Sprintf("%d %s", p.warnings, Pluralize("warning", p.warnings))
+	stotal := fmt.Sprintf("%d %s", p.suggestions, Pluralize("suggestion", p.suggestions))
 
-	if errors > 0 || warnings > 0 {
+	if p.errors > 0 || p.warnings > 0 {
 		symbol = "\u2716"
 	} else {
 		symbol = "\u2714"
 	}
 
-	n := len(linted)
+	n := p.files
 	fmt.Printf("%s %s, %s and %s in %d %s.\n", symbol,
 		colorize(etotal, errorColor), colorize(wtotal, warningColor),
-		colorize(stotal, suggestionColor), n, pluralize("file", n))
+		colorize(stotal, suggestionColor), n, Pluralize("file", n))
 
-	return errors != 0
+	return p.errors != 0, nil
 }
 
 // printVerboseAlert includes an alert's line, column, level, and message.
@@ -13,7 +13,9 @@ func fixOutputSpacing(msg string) string {
 	return msg
 }
 
-func pluralize(s string, n int) string {
+// Pluralize returns the plural of the noun `s` (e.g., "alert") if there are
+// `n` != 1 of them.
+func Pluralize(s string, n int) string {
 	if n != 1 {
 		return s + "s"
 	}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// PrintGates writes a summary of the quality gates in `results` to `w`,
+// returning whether or not any of them failed.
+func PrintGates(w io.Writer, results []core.GateResult) bool {
+	failed := 0
+	fmt.Fprintln(w, "\nQuality gates:")
+	for _, r := range results {
+		actual, limit := formatGate(r.Actual), formatGate(r.Limit)
+		if r.Passed() {
+			fmt.Fprintf(w, "  ✔ %s: %s (limit %s)\n", r.Gate, actual, limit)
+		} else {
+			failed++
+			fmt.Fprintf(w, "  %s %s: %s (limit %s)\n",
+				colorize("✖", errorColor), r.Gate, colorize(actual, errorColor), limit)
+		}
+	}
+
+	n := len(results)
+	if failed > 0 {
+		fmt.Fprintf(w, "%s %d of %d quality %s failed.\n",
+			colorize("✖", errorColor), failed, n, Pluralize("gate", n))
+	} else {
+		fmt.Fprintf(w, "✔ %d quality %s passed.\n", n, Pluralize("gate", n))
+	}
+	return failed > 0
+}
+
+func formatGate(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,325 @@
+package ui
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// An htmlAlert is an Alert along with the details we need to filter it.
+type htmlAlert struct {
+	core.Alert
+	ID    int
+	Style string
+}
+
+// An htmlSegment is a run of a line that's covered by the same alerts.
+type htmlSegment struct {
+	Text     string
+	Alerts   []*htmlAlert
+	Severity string // the most severe level of `Alerts`
+}
+
+// IDs returns the space-separated IDs of the segment's alerts.
+func (s htmlSegment) IDs() string {
+	ids := []string{}
+	for _, a := range s.Alerts {
+		ids = append(ids, strconv.Itoa(a.ID))
+	}
+	return strings.Join(ids, " ")
+}
+
+type htmlLine struct {
+	Number   int
+	Segments []htmlSegment
+}
+
+type htmlFile struct {
+	Path   string
+	Alerts []*htmlAlert
+	Lines  []htmlLine
+}
+
+// An htmlBar is one bar of a summary chart.
+type htmlBar struct {
+	Label   string
+	Count   int
+	Percent int // relative to the chart's largest bar
+}
+
+type htmlReport struct {
+	Files      []htmlFile
+	Total      int
+	Severities []htmlBar
+	Styles     []htmlBar
+	Rules      []htmlBar
+}
+
+// severityRank orders alert levels from least to most severe.
+var severityRank = map[string]int{"suggestion": 0, "warning": 1, "error": 2}
+
+// PrintHTMLAlerts prints Alerts as a self-contained HTML report, which shows
+// each file's source with its alerts highlighted.
+func PrintHTMLAlerts(linted []*core.File) bool {
+	alertCount := 0
+	report := htmlReport{}
+	severities, styles, rules := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, f := range linted {
+		file := htmlFile{Path: filepath.ToSlash(f.Path)}
+		for _, a := range f.SortedAlerts() {
+			if a.Severity == "error" {
+				alertCount++
+			}
+			a.Message = fixOutputSpacing(a.Message)
+			alert := &htmlAlert{
+				Alert: a, ID: report.Total, Style: strings.Split(a.Check, ".")[0]}
+			file.Alerts = append(file.Alerts, alert)
+			severities[a.Severity]++
+			styles[alert.Style]++
+			rules[a.Check]++
+			report.Total++
+		}
+		file.Lines = htmlLines(f.Lines, file.Alerts)
+		report.Files = append(report.Files, file)
+	}
+
+	report.Severities = htmlBars(severities, func(a, b string) bool {
+		return severityRank[a] > severityRank[b]
+	})
+	report.Styles = htmlBars(styles, nil)
+	report.Rules = htmlBars(rules, nil)
+
+	if err := htmlTemplate.Execute(os.Stdout, report); err != nil {
+		fmt.Println(err)
+	}
+	return alertCount != 0
+}
+
+// htmlLines splits each line of `lines` into segments according to the spans
+// of `alerts`.
+func htmlLines(lines []string, alerts []*htmlAlert) []htmlLine {
+	byLine := map[int][]*htmlAlert{}
+	for _, a := range alerts {
+		byLine[a.Line] = append(byLine[a.Line], a)
+	}
+
+	formatted := []htmlLine{}
+	for i, line := range lines {
+		runes := []rune(strings.TrimRight(line, "\r\n"))
+		if i == len(lines)-1 && len(runes) == 0 {
+			break
+		}
+
+		// We split the line at every column where an alert starts or ends.
+		cuts := map[int]bool{0: true, len(runes): true}
+		for _, a := range byLine[i+1] {
+			start, end := clampSpan(a.Span, len(runes))
+			cuts[start], cuts[end] = true, true
+		}
+		cols := []int{}
+		for c := range cuts {
+			cols = append(cols, c)
+		}
+		sort.Ints(cols)
+
+		formatted = append(formatted, htmlLine{Number: i + 1})
+		for j := 0; j < len(cols)-1; j++ {
+			seg := htmlSegment{Text: string(runes[cols[j]:cols[j+1]])}
+			for _, a := range byLine[i+1] {
+				start, end := clampSpan(a.Span, len(runes))
+				if start <= cols[j] && cols[j+1] <= end {
+					seg.Alerts = append(seg.Alerts, a)
+					if seg.Severity == "" || severityRank[a.Severity] > severityRank[seg.Severity] {
+						seg.Severity = a.Severity
+					}
+				}
+			}
+			formatted[len(formatted)-1].Segments = append(
+				formatted[len(formatted)-1].Segments, seg)
+		}
+	}
+	return formatted
+}
+
+// clampSpan converts `span` (1-based and inclusive) into a 0-based, half-open
+// range within a line of `length` runes.
+func clampSpan(span []int, length int) (int, int) {
+	start, end := span[0]-1, span[1]
+	if start < 0 {
+		start = 0
+	}
+	if end > length {
+		end = length
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// htmlBars converts `counts` into a chart's bars, sorted by `less` or, if
+// it's nil, by count.
+func htmlBars(counts map[string]int, less func(a, b string) bool) []htmlBar {
+	max := 0
+	bars := []htmlBar{}
+	for label, count := range counts {
+		bars = append(bars, htmlBar{Label: label, Count: count})
+		if count > max {
+			max = count
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if less != nil {
+			return less(bars[i].Label, bars[j].Label)
+		} else if bars[i].Count != bars[j].Count {
+			return bars[i].Count > bars[j].Count
+		}
+		return bars[i].Label < bars[j].Label
+	})
+	for i := range bars {
+		bars[i].Percent = 100 * bars[i].Count / max
+	}
+	return bars
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"plural": Pluralize,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Vale report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 72em; padding: 1em 2em; color: #24292e; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; }
+.charts { display: flex; flex-wrap: wrap; gap: 2em; }
+.chart { flex: 1; min-width: 16em; }
+.chart table { width: 100%; border-collapse: collapse; font-size: 0.9em; }
+.chart td { padding: 2px 4px; }
+.chart td.label { white-space: nowrap; }
+.chart td.count { text-align: right; width: 3em; }
+.bar { height: 0.9em; background: #6a737d; }
+.bar.error, mark.error { background: #f9c0c0; }
+.bar.warning, mark.warning { background: #fbe2a4; }
+.bar.suggestion, mark.suggestion { background: #c8e1ff; }
+.filters { position: sticky; top: 0; background: #fff; padding: 0.5em 0; border-bottom: 1px solid #e1e4e8; z-index: 20; }
+.filters label { margin-right: 1em; }
+details { margin: 1em 0; border: 1px solid #e1e4e8; border-radius: 4px; }
+summary { cursor: pointer; padding: 0.5em; background: #f6f8fa; font-family: monospace; }
+.source { font-family: SFMono-Regular, Consolas, monospace; font-size: 0.85em; white-space: pre-wrap; word-break: break-word; padding: 0.5em 0; }
+.line { display: flex; }
+.ln { flex: none; width: 4em; padding-right: 1em; text-align: right; color: #959da5; user-select: none; }
+mark { position: relative; color: inherit; border-radius: 2px; cursor: help; }
+mark.filtered { background: none; cursor: auto; }
+.tip { display: none; position: absolute; left: 0; top: 100%; z-index: 10; width: 28em; padding: 0.5em; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; white-space: normal; background: #fff; border: 1px solid #d1d5da; border-radius: 4px; box-shadow: 0 2px 8px rgba(0, 0, 0, 0.15); }
+mark:not(.filtered):hover .tip { display: block; }
+.tip p { margin: 0.25em 0; }
+.check { font-family: monospace; color: #586069; }
+.alerts { width: 100%; border-collapse: collapse; font-size: 0.9em; }
+.alerts td, .alerts th { text-align: left; padding: 4px 8px; border-top: 1px solid #e1e4e8; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<h1>Vale report</h1>
+<p>{{.Total}} {{plural "alert" .Total}} in {{len .Files}} {{plural "file" (len .Files)}}.</p>
+<div class="charts">
+<div class="chart"><h2>By severity</h2><table>
+{{- range .Severities}}
+<tr><td class="label">{{.Label}}</td><td class="count">{{.Count}}</td><td><div class="bar {{.Label}}" style="width: {{.Percent}}%"></div></td></tr>
+{{- end}}
+</table></div>
+<div class="chart"><h2>By style</h2><table>
+{{- range .Styles}}
+<tr><td class="label">{{.Label}}</td><td class="count">{{.Count}}</td><td><div class="bar" style="width: {{.Percent}}%"></div></td></tr>
+{{- end}}
+</table></div>
+<div class="chart"><h2>By rule</h2><table>
+{{- range .Rules}}
+<tr><td class="label">{{.Label}}</td><td class="count">{{.Count}}</td><td><div class="bar" style="width: {{.Percent}}%"></div></td></tr>
+{{- end}}
+</table></div>
+</div>
+<div class="filters">
+<label><input type="checkbox" class="severity" value="error" checked> Errors</label>
+<label><input type="checkbox" class="severity" value="warning" checked> Warnings</label>
+<label><input type="checkbox" class="severity" value="suggestion" checked> Suggestions</label>
+<label>Style <select id="style"><option value="">All</option>{{range .Styles}}<option>{{.Label}}</option>{{end}}</select></label>
+<label>Rule <select id="rule"><option value="">All</option>{{range .Rules}}<option>{{.Label}}</option>{{end}}</select></label>
+<span id="showing"></span>
+</div>
+{{- range .Files}}
+<details{{if .Alerts}} open{{end}}>
+<summary>{{.Path}} ({{len .Alerts}} {{plural "alert" (len .Alerts)}})</summary>
+<div class="source">
+{{- range .Lines}}
+<div class="line"><span class="ln">{{.Number}}</span><span>
+{{- range .Segments}}
+{{- if .Alerts}}<mark class="{{.Severity}}" data-ids="{{.IDs}}">{{.Text}}<span class="tip">
+{{- range .Alerts}}<span class="alert" data-id="{{.ID}}"><p><strong>{{.Severity}}</strong> <span class="check">{{.Check}}</span></p><p>{{.Message}}</p>
+{{- if .Description}}<p>{{.Description}}</p>{{end}}
+{{- if .Link}}<p><a href="{{.Link}}">{{.Link}}</a></p>{{end}}</span>
+{{- end}}</span></mark>
+{{- else}}{{.Text}}{{end}}
+{{- end}}</span></div>
+{{- end}}
+</div>
+{{- if .Alerts}}
+<table class="alerts">
+<tr><th>Line</th><th>Severity</th><th>Rule</th><th>Message</th></tr>
+{{- range .Alerts}}
+<tr class="alert" data-id="{{.ID}}" data-severity="{{.Severity}}" data-style="{{.Style}}" data-rule="{{.Check}}"><td>{{.Line}}:{{index .Span 0}}</td><td>{{.Severity}}</td><td class="check">{{.Check}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</details>
+{{- end}}
+<script>
+(function () {
+  var rows = document.querySelectorAll("tr.alert");
+  var rank = {filtered: -1, suggestion: 0, warning: 1, error: 2}, levels = {};
+  rows.forEach(function (row) { levels[row.dataset.id] = row.dataset.severity; });
+  function update() {
+    var severities = {}, visible = {}, shown = 0;
+    document.querySelectorAll("input.severity").forEach(function (box) {
+      severities[box.value] = box.checked;
+    });
+    var style = document.getElementById("style").value;
+    var rule = document.getElementById("rule").value;
+    rows.forEach(function (row) {
+      var d = row.dataset;
+      var show = severities[d.severity] && (!style || d.style === style) && (!rule || d.rule === rule);
+      row.classList.toggle("hidden", !show);
+      visible[d.id] = show;
+      if (show) { shown++; }
+    });
+    document.querySelectorAll(".tip .alert").forEach(function (tip) {
+      tip.classList.toggle("hidden", !visible[tip.dataset.id]);
+    });
+    document.querySelectorAll("mark").forEach(function (mark) {
+      // A highlight takes the level of its most severe visible alert.
+      var level = "filtered";
+      mark.dataset.ids.split(" ").forEach(function (id) {
+        if (visible[id] && rank[levels[id]] > rank[level]) { level = levels[id]; }
+      });
+      mark.className = level;
+    });
+    document.getElementById("showing").textContent = "Showing " + shown + " of " + rows.length + " alerts";
+  }
+  document.querySelectorAll(".filters input, .filters select").forEach(function (el) {
+    el.addEventListener("change", update);
+  });
+  update();
+})();
+</script>
+</body>
+</html>
+`))
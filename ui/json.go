@@ -7,8 +7,18 @@ import (
 	"github.com/ValeLint/vale/core"
 )
 
-// PrintJSONAlerts prints Alerts in map[file.path][]Alert form.
-func PrintJSONAlerts(linted []*core.File) bool {
+// A fileMetrics holds a file's alerts alongside its statistics.
+type fileMetrics struct {
+	Alerts []core.Alert
+	Stats  core.Stats
+}
+
+// PrintJSONAlerts prints Alerts in map[file.path][]Alert form or, if
+// `metrics` is set, in map[file.path]{Alerts, Stats} form.
+//
+// Any errors -- `errs`, from loading our rules, and those of each file -- are
+// listed under an additional "errors" key.
+func PrintJSONAlerts(linted []*core.File, metrics bool, errs []core.Error) bool {
 	alertCount := 0
 	formatted := map[string][]core.Alert{}
 	for _, f := range linted {
@@ -20,7 +30,30 @@ func PrintJSONAlerts(linted []*core.File) bool {
 			formatted[f.Path] = append(formatted[f.Path], a)
 		}
 	}
-	b, err := json.MarshalIndent(formatted, "", "  ")
+
+	output := map[string]interface{}{}
+	for path, alerts := range formatted {
+		output[path] = alerts
+	}
+	if metrics {
+		for _, f := range linted {
+			alerts := formatted[f.Path]
+			if alerts == nil {
+				alerts = []core.Alert{}
+			}
+			output[f.Path] = fileMetrics{Alerts: alerts, Stats: f.Stats()}
+		}
+	}
+
+	errs = append([]core.Error{}, errs...)
+	for _, f := range linted {
+		errs = append(errs, f.Errors...)
+	}
+	if len(errs) > 0 {
+		output["errors"] = errs
+	}
+
+	b, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		fmt.Println(err)
 	} else {
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ValeLint/vale/core"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	Skipped   []junitSkipped `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// PrintJUnitAlerts prints Alerts as a JUnit XML report, treating each file as
+// a test case and each of its alerts as a failure.
+//
+// Errors are reported as failures, while warnings and suggestions (which
+// don't affect our exit code) are reported as skipped.
+func PrintJUnitAlerts(linted []*core.File) bool {
+	report, alertCount := newJUnitReport(linted)
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println(xml.Header + string(b))
+	}
+	return alertCount != 0
+}
+
+// newJUnitReport builds the report for `linted`, along with its number of
+// error-level alerts.
+func newJUnitReport(linted []*core.File) (junitTestSuites, int) {
+	alertCount := 0
+	suite := junitTestSuite{Name: "vale"}
+	for _, f := range linted {
+		path := filepath.ToSlash(f.Path)
+		tc := junitTestCase{ClassName: "vale", Name: path}
+		for _, a := range f.SortedAlerts() {
+			msg := fixOutputSpacing(a.Message)
+			text := fmt.Sprintf("%s:%d:%d: %s (%s)", path, a.Line, a.Span[0], msg, a.Severity)
+			if a.Severity == "error" {
+				alertCount++
+				tc.Failures = append(tc.Failures, junitFailure{Message: msg, Type: a.Check, Text: text})
+			} else {
+				tc.Skipped = append(tc.Skipped, junitSkipped{Message: msg, Text: text})
+			}
+		}
+
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		} else if len(tc.Skipped) > 0 {
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	report := junitTestSuites{
+		Name: "vale", Tests: suite.Tests, Failures: suite.Failures,
+		Skipped: suite.Skipped, Suites: []junitTestSuite{suite}}
+	return report, alertCount
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnitReport(t *testing.T) {
+	config := core.NewConfig()
+	a := core.NewFileFromString("", "a.md", config)
+	a.Alerts = []core.Alert{
+		{Check: "vale.Editorializing", Severity: "error", Line: 1, Span: []int{7, 10}},
+		{Check: "vale.Hedging", Severity: "warning", Line: 2, Span: []int{1, 4}},
+		{Check: "vale.Redundancy", Severity: "error", Line: 3, Span: []int{1, 4}, Message: "'ATM machine' is redundant"},
+	}
+	b := core.NewFileFromString("", "b.md", config)
+	b.Alerts = []core.Alert{
+		{Check: "vale.Annotations", Severity: "suggestion", Line: 1, Span: []int{1, 4}},
+	}
+	c := core.NewFileFromString("", "c.md", config)
+
+	report, n := newJUnitReport([]*core.File{a, b, c})
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []int{3, 1, 1}, []int{report.Tests, report.Failures, report.Skipped})
+	assert.Len(t, report.Suites, 1)
+
+	// One test case per file, with a failure (or skip) per alert.
+	cases := report.Suites[0].Cases
+	assert.Len(t, cases, 3)
+	for i, expected := range []struct {
+		name              string
+		failures, skipped int
+	}{
+		{"a.md", 2, 1},
+		{"b.md", 0, 1},
+		{"c.md", 0, 0},
+	} {
+		assert.Equal(t, expected.name, cases[i].Name)
+		assert.Len(t, cases[i].Failures, expected.failures, expected.name)
+		assert.Len(t, cases[i].Skipped, expected.skipped, expected.name)
+	}
+	assert.Equal(t, "vale.Redundancy", cases[0].Failures[1].Type)
+	assert.Equal(t, "a.md:3:1: 'ATM machine' is redundant (error)", cases[0].Failures[1].Text)
+}
@@ -7,32 +7,43 @@ import (
 	"github.com/ValeLint/vale/core"
 )
 
-// PrintLineAlerts prints Alerts in <path>:<line>:<col>:<check>:<message> format.
-func PrintLineAlerts(linted []*core.File, relative bool) bool {
+type linePrinter struct {
+	relative bool
+	errors   bool
+}
+
+// NewLinePrinter creates a Printer that prints Alerts in
+// <path>:<line>:<col>:<check>:<message> format.
+func NewLinePrinter(relative bool) Printer {
+	return &linePrinter{relative: relative}
+}
+
+func (p *linePrinter) Print(f *core.File) error {
 	var base string
 
-	alertCount := 0
-	for _, f := range linted {
-		// If vale is run from a parent directory of f, we use a shorter file
-		// path -- e.g., if run from the directory 'vale', we use
-		// 'testdata/test.cc: ...' instead of
-		// /Users/.../.../.../vale/testdata/test.cc: ...'.
-		if relative && strings.Contains(f.Path, core.ExeDir) {
-			// FIXME: This doesn't work as intended, but our tests rely on its
-			// output -- so, we hide it behind a flag for now.
-			base = strings.Split(f.Path, core.ExeDir)[1]
-		} else {
-			base = f.Path
-		}
+	// If vale is run from a parent directory of f, we use a shorter file
+	// path -- e.g., if run from the directory 'vale', we use
+	// 'testdata/test.cc: ...' instead of
+	// /Users/.../.../.../vale/testdata/test.cc: ...'.
+	if p.relative && strings.Contains(f.Path, core.ExeDir) {
+		// FIXME: This doesn't work as intended, but our tests rely on its
+		// output -- so, we hide it behind a flag for now.
+		base = strings.Split(f.Path, core.ExeDir)[1]
+	} else {
+		base = f.Path
+	}
 
-		for _, a := range f.SortedAlerts() {
-			if a.Severity == "error" {
-				alertCount++
-			}
-			a.Message = fixOutputSpacing(a.Message)
-			fmt.Print(fmt.Sprintf("%s:%d:%d:%s:%s\n",
-				base, a.Line, a.Span[0], a.Check, a.Message))
+	for _, a := range f.SortedAlerts() {
+		if a.Severity == "error" {
+			p.errors = true
 		}
+		a.Message = fixOutputSpacing(a.Message)
+		fmt.Print(fmt.Sprintf("%s:%d:%d:%s:%s\n",
+			base, a.Line, a.Span[0], a.Check, a.Message))
 	}
-	return alertCount != 0
+	return nil
+}
+
+func (p *linePrinter) Close() (bool, error) {
+	return p.errors, nil
 }
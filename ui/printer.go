@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// A Printer prints the alerts of each file as it's linted.
+//
+// Styles that need every file up front (e.g., JSON) buffer them until Close
+// is called; see NewBufferedPrinter.
+type Printer interface {
+	// Print prints (or records) the alerts of `f`.
+	Print(f *core.File) error
+	// Close prints anything that depends on every file, such as a summary,
+	// and returns whether or not there were any errors.
+	Close() (bool, error)
+}
+
+// A BatchFunc prints the alerts of every file at once.
+type BatchFunc func(linted []*core.File) (bool, error)
+
+type bufferedPrinter struct {
+	print  BatchFunc
+	linted []*core.File
+}
+
+// NewBufferedPrinter creates a Printer that holds on to every file until it's
+// closed, at which point it calls `print`.
+func NewBufferedPrinter(print BatchFunc) Printer {
+	return &bufferedPrinter{print: print}
+}
+
+func (p *bufferedPrinter) Print(f *core.File) error {
+	p.linted = append(p.linted, f)
+	return nil
+}
+
+func (p *bufferedPrinter) Close() (bool, error) {
+	return p.print(p.linted)
+}
+
+type sortedPrinter struct {
+	printer Printer
+	linted  []*core.File
+}
+
+// NewSortedPrinter creates a Printer that passes every file to `printer`,
+// sorted by path, once it's closed.
+func NewSortedPrinter(printer Printer) Printer {
+	return &sortedPrinter{printer: printer}
+}
+
+func (p *sortedPrinter) Print(f *core.File) error {
+	p.linted = append(p.linted, f)
+	return nil
+}
+
+func (p *sortedPrinter) Close() (bool, error) {
+	sort.Sort(core.ByName(p.linted))
+	for _, f := range p.linted {
+		if err := p.printer.Print(f); err != nil {
+			return false, err
+		}
+	}
+	return p.printer.Close()
+}
+
+// PrintAll prints every file in `linted` with `printer`.
+func PrintAll(printer Printer, linted []*core.File) (bool, error) {
+	for _, f := range linted {
+		if err := printer.Print(f); err != nil {
+			return false, err
+		}
+	}
+	return printer.Close()
+}
+
+// A jsonLine is a single alert in `--output=jsonl`.
+type jsonLine struct {
+	Path string
+	core.Alert
+}
+
+type jsonLinesPrinter struct {
+	encoder *json.Encoder
+	errors  bool
+}
+
+// NewJSONLinesPrinter creates a Printer that writes each alert as a JSON
+// object on its own line (i.e., newline-delimited JSON).
+func NewJSONLinesPrinter() Printer {
+	return &jsonLinesPrinter{encoder: json.NewEncoder(os.Stdout)}
+}
+
+func (p *jsonLinesPrinter) Print(f *core.File) error {
+	for _, a := range f.SortedAlerts() {
+		if a.Severity == "error" {
+			p.errors = true
+		}
+		a.Message = fixOutputSpacing(a.Message)
+		if err := p.encoder.Encode(jsonLine{Path: f.Path, Alert: a}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *jsonLinesPrinter) Close() (bool, error) {
+	return p.errors, nil
+}
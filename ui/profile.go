@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// PrintProfile writes the `n` slowest rules and files recorded by `p` to `w`,
+// along with the time spent in each other phase of linting (e.g., converting
+// markup).
+//
+// A rule's time includes the CheckPOS calls it makes, and a file's includes
+// everything done while linting it.
+func PrintProfile(w io.Writer, p *core.Profile, n int) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "\nSlowest rules:")
+	fmt.Fprintln(tw, "RULE\tCALLS\tTOTAL\tMAX\tSLOWEST FILE")
+	for _, t := range p.Rules(n) {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n",
+			t.Name, t.Calls, formatDuration(t.Total), formatDuration(t.Max), t.Worst)
+	}
+
+	fmt.Fprintln(tw, "\nPhases:")
+	fmt.Fprintln(tw, "PHASE\tCALLS\tTOTAL\tMAX")
+	for _, t := range p.Phases() {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n",
+			t.Name, t.Calls, formatDuration(t.Total), formatDuration(t.Max))
+	}
+
+	fmt.Fprintln(tw, "\nSlowest files:")
+	fmt.Fprintln(tw, "FILE\tTOTAL\tSLOWEST RULE")
+	for _, t := range p.Files(n) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Name, formatDuration(t.Total), t.Worst)
+	}
+
+	tw.Flush()
+}
+
+// formatDuration formats `d` in milliseconds, which keeps the columns of a
+// profile comparable.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ValeLint/vale/check"
+)
+
+// PrintRules prints a description of each of the given rules, either as a
+// table or as JSON.
+func PrintRules(rules []check.RuleInfo, asJSON bool) {
+	if asJSON {
+		b, err := json.MarshalIndent(rules, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println(string(b))
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTYLE\tEXTENDS\tLEVEL\tSCOPE\tSECTIONS\tFILE")
+	for _, r := range rules {
+		sections, path := strings.Join(r.Sections, ","), r.Path
+		if sections == "" {
+			sections = "-"
+		}
+		if path == "" {
+			path = "(built-in)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Name, r.Style, r.Extends, r.Level, r.Scope, sections, path)
+	}
+	w.Flush()
+}
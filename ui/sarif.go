@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ValeLint/vale/check"
+	"github.com/ValeLint/vale/core"
+)
+
+const (
+	sarifSchema  = "https://schemastore.azurewebsites.net/schemas/json/sarif-2.1.0-rtm.5.json"
+	sarifVersion = "2.1.0"
+	valeURI      = "https://github.com/ValeLint/vale"
+	sarifRootID  = "SRCROOT"
+)
+
+// severityToSARIF maps Vale's alert levels to SARIF's result levels.
+var severityToSARIF = map[string]string{
+	"suggestion": "note",
+	"warning":    "warning",
+	"error":      "error",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	ColumnKind         string                           `json:"columnKind"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration *sarifRuleDefaults `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// PrintSARIFAlerts prints Alerts as a SARIF 2.1.0 log, using `checks` to
+// describe each rule.
+func PrintSARIFAlerts(linted []*core.File, checks map[string]check.Check, version string) bool {
+	alertCount := 0
+
+	names := []string{}
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := []sarifRule{}
+	ruleToIndex := map[string]int{}
+	for _, name := range names {
+		rules = append(rules, makeSARIFRule(name, checks[name]))
+		ruleToIndex[name] = len(rules) - 1
+	}
+
+	root := sarifRoot()
+	results := []sarifResult{}
+	for _, f := range linted {
+		artifact := makeSARIFArtifact(f.Path, root)
+		for _, a := range f.SortedAlerts() {
+			if a.Severity == "error" {
+				alertCount++
+			}
+
+			idx, ok := ruleToIndex[a.Check]
+			if !ok {
+				// Some checks (e.g., those extending `consistency`) report
+				// alerts under a different name than the one they're loaded as.
+				rules = append(rules, sarifRule{ID: a.Check})
+				idx = len(rules) - 1
+				ruleToIndex[a.Check] = idx
+			}
+
+			results = append(results, sarifResult{
+				RuleID:    a.Check,
+				RuleIndex: idx,
+				Level:     severityToSARIF[a.Severity],
+				Message:   sarifMessage{Text: fixOutputSpacing(a.Message)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: artifact,
+						Region: sarifRegion{
+							StartLine:   a.Line,
+							StartColumn: a.Span[0],
+							// SARIF's end column is exclusive, while ours
+							// isn't.
+							EndColumn: a.Span[1] + 1,
+						},
+					},
+				}},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name: "vale", Version: version, InformationURI: valeURI,
+				Rules: rules}},
+			OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+				sarifRootID: {URI: fileURI(root) + "/"}},
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+
+	b, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println(string(b))
+	}
+	return alertCount != 0
+}
+
+// sarifRoot returns the directory that we make artifact paths relative to:
+// the root of the current git repository or, outside of one, the current
+// directory.
+func sarifRoot() string {
+	root, err := core.GitRoot()
+	if err != nil {
+		root, _ = os.Getwd()
+	}
+	return root
+}
+
+// makeSARIFArtifact locates the file `path` relative to `root` or, if it's
+// outside of `root`, by its absolute URI (without a base).
+func makeSARIFArtifact(path, root string) sarifArtifactLocation {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return sarifArtifactLocation{URI: (&url.URL{Path: filepath.ToSlash(path)}).String()}
+	} else if rel, ok := relativeTo(root, abs); ok {
+		return sarifArtifactLocation{
+			URI: (&url.URL{Path: rel}).String(), URIBaseID: sarifRootID}
+	}
+	return sarifArtifactLocation{URI: fileURI(abs)}
+}
+
+// relativeTo returns the absolute path `path` relative to `root` (with
+// forward slashes), if it's inside of `root`.
+//
+// We compare the real directories, since git reports its root with any
+// symbolic links resolved.
+func relativeTo(root, path string) (string, bool) {
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		root = real
+	}
+	if real, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(real, filepath.Base(path))
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// fileURI converts the absolute path `path` into a "file" URI (e.g.,
+// "C:\docs\a.md" => "file:///C:/docs/a.md").
+func fileURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
+
+func makeSARIFRule(name string, chk check.Check) sarifRule {
+	rule := sarifRule{ID: name, HelpURI: chk.Link}
+	if chk.Description != "" {
+		rule.ShortDescription = &sarifMessage{Text: fixOutputSpacing(chk.Description)}
+	}
+	for level, n := range core.LevelToInt {
+		if n == chk.Level {
+			rule.DefaultConfiguration = &sarifRuleDefaults{
+				Level: severityToSARIF[level]}
+		}
+	}
+	return rule
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIFArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sarif")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "repo")
+	for _, d := range []string{"repo/docs", "repo2", "other dir"} {
+		assert.Nil(t, os.MkdirAll(filepath.Join(dir, d), 0755))
+	}
+	assert.Nil(t, os.Symlink(root, filepath.Join(dir, "link")))
+
+	for _, tt := range []struct {
+		path string
+		uri  string // relative to root, if `base` is set
+		base bool
+	}{
+		{"repo/docs/a b.md", "docs/a%20b.md", true},
+		{"link/docs/a.md", "docs/a.md", true},
+		{"repo2/a.md", "repo2/a.md", false},
+		{"other dir/a.md", "other%20dir/a.md", false},
+	} {
+		artifact := makeSARIFArtifact(filepath.Join(dir, tt.path), root)
+		if tt.base {
+			assert.Equal(t, tt.uri, artifact.URI, tt.path)
+			assert.Equal(t, sarifRootID, artifact.URIBaseID, tt.path)
+		} else {
+			// Anything outside of the root gets an absolute URI, never "../".
+			assert.True(t, strings.HasPrefix(artifact.URI, "file:///"), tt.path)
+			assert.True(t, strings.HasSuffix(artifact.URI, "/"+tt.uri), tt.path)
+			assert.NotContains(t, artifact.URI, "..", tt.path)
+			assert.Empty(t, artifact.URIBaseID, tt.path)
+		}
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	assert.Equal(t, "file:///tmp/a%20b.md", fileURI(filepath.FromSlash("/tmp/a b.md")))
+}
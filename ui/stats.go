@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ValeLint/vale/core"
+)
+
+// PrintStats prints the statistics of each file in `linted` and of all of
+// them together, either as a table or as JSON.
+//
+// The table only includes the Flesch reading ease, Flesch-Kincaid grade level
+// and Dale-Chall score; the JSON includes every readability metric.
+func PrintStats(linted []*core.File, asJSON bool) {
+	sort.Slice(linted, func(i, j int) bool { return linted[i].Path < linted[j].Path })
+
+	if asJSON {
+		files := map[string]core.Stats{}
+		for _, f := range linted {
+			files[f.Path] = f.Stats()
+		}
+		b, err := json.MarshalIndent(struct {
+			Files map[string]core.Stats
+			Total core.Stats
+		}{files, core.TotalStats(linted)}, "", "  ")
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println(string(b))
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "FILE\tWORDS\tSENTENCES\tPARAGRAPHS\tHEADINGS\tMINUTES\tALERTS/1K\tEASE\tGRADE\tDALE-CHALL\t")
+	for _, f := range linted {
+		printStatsRow(w, f.Path, f.Stats())
+	}
+	if len(linted) > 1 {
+		printStatsRow(w, "Total", core.TotalStats(linted))
+	}
+	w.Flush()
+}
+
+func printStatsRow(w *tabwriter.Writer, name string, s core.Stats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+		name, s.Words, s.Sentences, s.Paragraphs, s.Headings, s.ReadingTime,
+		s.AlertDensity, s.Readability.FleschReadingEase,
+		s.Readability.FleschKincaid, s.Readability.DaleChall)
+}
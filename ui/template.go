@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/fatih/color"
+)
+
+// templateFuncs are the helpers available to output templates:
+//
+//	rel           returns a path relative to the current directory
+//	jsonEscape    escapes a string for use within a JSON string
+//	toJSON        encodes any value as JSON
+//	severity      colors a severity (e.g., "error") according to its level
+//	colorize      colors text according to the given severity
+//	fixSpacing    puts an alert message on one line
+var templateFuncs = template.FuncMap{
+	"rel":        relativePath,
+	"jsonEscape": jsonEscape,
+	"toJSON":     toJSON,
+	"severity":   func(level string) string { return colorize(level, severityColor(level)) },
+	"colorize":   func(level, s string) string { return colorize(s, severityColor(level)) },
+	"fixSpacing": fixOutputSpacing,
+	"plural":     Pluralize,
+}
+
+// IsTemplate determines if the output style `output` refers to a template.
+func IsTemplate(output string) bool {
+	return filepath.Ext(output) == ".tmpl"
+}
+
+// ParseTemplate reads the output template at `path` (a Go text/template),
+// making our helpers available to it.
+func ParseTemplate(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+}
+
+// PrintTemplateAlerts renders `tmpl` (see ParseTemplate) with `linted` as its
+// data.
+func PrintTemplateAlerts(linted []*core.File, tmpl *template.Template) (bool, error) {
+	if err := tmpl.Execute(os.Stdout, linted); err != nil {
+		return false, err
+	}
+
+	for _, f := range linted {
+		for _, a := range f.Alerts {
+			if a.Severity == "error" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func severityColor(level string) color.Attribute {
+	switch level {
+	case "error":
+		return errorColor
+	case "warning":
+		return warningColor
+	}
+	return suggestionColor
+}
+
+func relativePath(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func jsonEscape(s string) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b[1 : len(b)-1]), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ValeLint/vale/core"
+	"github.com/ValeLint/vale/lint"
+)
+
+// watchInterval is how often we check the watched files for changes.
+const watchInterval = 500 * time.Millisecond
+
+// watchFiles lints `paths` and then re-lints each file that changes, without
+// exiting.
+//
+// Our checks are only reloaded when the config file or a style's YAML
+// changes, since that's where most of our start-up time is spent. If they
+// can't be reloaded (e.g., because of a typo in .vale.ini), we report why and
+// keep watching with the previous ones.
+//
+// Any quality gates are checked against the latest results for every file,
+// not just the ones that changed.
+func watchFiles(config *core.Config, paths []string, pat string, baseline core.Baseline) error {
+	linter, err := newLinter(config)
+	if err != nil {
+		return err
+	} else if _, err = newPrinter(linter); err != nil {
+		// We'd otherwise only find out about a bad template after linting.
+		return err
+	}
+	styles := lint.StylesSnapshot(config)
+	files, err := linter.Snapshot(paths, pat)
+	if err != nil {
+		return err
+	}
+
+	results := map[string]*core.File{}
+	linted, err := linter.Lint(paths, pat)
+	if err != nil {
+		return err
+	}
+	if err = printWatched(linted, linter, baseline, results, files); err != nil {
+		return err
+	}
+
+	for range time.Tick(watchInterval) {
+		// We only take a new snapshot once we've linted it, so that we retry
+		// any files we couldn't lint.
+		var current lint.Snapshot
+		if latest := lint.StylesSnapshot(linter.Config); !latest.Equal(styles) {
+			reloaded, err := reloadLinter(linter.Config)
+			if err != nil {
+				// We only try each version of our config and rules once.
+				styles = latest
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			if current, err = reloaded.Snapshot(paths, pat); err == nil {
+				linted, err = reloaded.Lint(paths, pat)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			linter, styles = reloaded, lint.StylesSnapshot(reloaded.Config)
+			results = map[string]*core.File{}
+		} else {
+			if current, err = linter.Snapshot(paths, pat); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			changed := current.Changed(files)
+			if len(changed) == 0 {
+				files = current
+				continue
+			} else if linted, err = linter.Lint(changed, pat); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+		}
+
+		files = current
+		if err = printWatched(linted, linter, baseline, results, files); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reloadLinter creates a new Linter from the current version of `config`'s
+// file and styles.
+func reloadLinter(config *core.Config) (lint.Linter, error) {
+	reloaded, err := core.ReloadConfig(config)
+	if err != nil {
+		return lint.Linter{}, err
+	}
+	return newLinter(reloaded)
+}
+
+// printWatched prints the alerts in `linted` and, if our config has any, the
+// results of its quality gates for every file in `files` -- using `results`
+// to hold on to the files we've linted so far.
+func printWatched(linted []*core.File, linter lint.Linter, baseline core.Baseline, results map[string]*core.File, files lint.Snapshot) error {
+	applyBaseline(linted, baseline)
+	if _, err := printAlerts(linted, linter); err != nil {
+		return err
+	}
+
+	if !linter.Config.Gates.Enabled() {
+		return nil
+	}
+	for _, f := range linted {
+		results[f.Path] = f
+	}
+	all := []*core.File{}
+	for fp, f := range results {
+		if _, ok := files[filepath.FromSlash(fp)]; ok {
+			all = append(all, f)
+		} else {
+			// The file's been deleted (or ignored) since we linted it.
+			delete(results, fp)
+		}
+	}
+	sort.Sort(core.ByName(all))
+	printGates(all, linter.Config)
+	return nil
+}